@@ -1,6 +1,6 @@
 module github.com/aserto-dev/aserto-idp-plugin-azuread
 
-go 1.19
+go 1.22
 
 // replace github.com/aserto-dev/idp-plugin-sdk => ../idp-plugin-sdk
 
@@ -19,7 +19,7 @@ import (
 
 func init() {
 	// Set go version for docker builds
-	os.Setenv("GO_VERSION", "1.19")
+	os.Setenv("GO_VERSION", "1.22")
 	// Set private repositories
 	os.Setenv("VAULT_ADDR", "https://vault.eng.aserto.com")
 }
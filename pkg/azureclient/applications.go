@@ -0,0 +1,358 @@
+package azureclient
+
+import (
+	"context"
+	"sort"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/models"
+	adserviceprincipals "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/serviceprincipals"
+	spitem "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/serviceprincipals/item"
+	spapproleassignedto "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/serviceprincipals/item/approleassignedto"
+	adapproleassignments "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/users/item/approleassignments"
+	"github.com/google/uuid"
+	abs "github.com/microsoft/kiota-abstractions-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	appRoleAssignmentFields = []string{"id", "appRoleId", "resourceId", "resourceDisplayName"}
+	assignedToFields        = []string{"id", "appRoleId", "principalId", "principalType", "resourceId", "resourceDisplayName"}
+	servicePrincipalFields  = []string{"id", "appId", "displayName", "appRoles"}
+)
+
+// Principal types of app role assignments.
+const (
+	principalTypeUser  = "User"
+	principalTypeGroup = "Group"
+)
+
+// b2cExtensionsAppName starts the display name of the application Azure AD B2C stores custom user attributes on.
+const b2cExtensionsAppName = "b2c-extensions-app"
+
+// maxInValues is the largest number of values Graph accepts in the in operator of a directory object filter.
+const maxInValues = 15
+
+// AppAssignments are the users assigned to an enterprise application, read page by page.
+type AppAssignments struct {
+	// Users iterates over the assigned users, sorted by id.
+	Users *UserPageIterator
+	// Granted maps the ids of the assigned users onto the assignments granting them access: the ones made to
+	// the user and to the groups the user is a member of, directly or through nested groups.
+	Granted map[string][]models.AppRoleAssignmentable
+}
+
+// ListUserAppRoleAssignments returns the app roles granted to a user, directly or through the groups
+// the user is a direct member of.
+func (c *AzureADClient) ListUserAppRoleAssignments(userID string) ([]models.AppRoleAssignmentable, error) {
+	ctx := context.Background()
+
+	requestInfo, err := c.appRoleAssignmentsRequest(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	page, err := c.adapter.Send(ctx, requestInfo, models.CreateAppRoleAssignmentCollectionResponseFromDiscriminatorValue, graphErrorMapping)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list app role assignments of user %s: %s", userID, GraphErrorMessage(err))
+	}
+	assignments, _ := page.(models.AppRoleAssignmentCollectionResponseable)
+	return c.collectAppRoleAssignments(ctx, userID, assignments)
+}
+
+// ListUsersAppRoleAssignments returns, by user id, the app roles granted to each of the given users.
+// The first page of every user is requested through $batch.
+func (c *AzureADClient) ListUsersAppRoleAssignments(userIDs []string) (map[string][]models.AppRoleAssignmentable, error) {
+	ctx := context.Background()
+
+	requests := make([]*abs.RequestInformation, 0, len(userIDs))
+	for _, userID := range userIDs {
+		requestInfo, err := c.appRoleAssignmentsRequest(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, requestInfo)
+	}
+	pages, err := c.batchGet(ctx, requests, models.CreateAppRoleAssignmentCollectionResponseFromDiscriminatorValue)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments := make(map[string][]models.AppRoleAssignmentable, len(userIDs))
+	for i, userID := range userIDs {
+		page, ok := pages[i].(models.AppRoleAssignmentCollectionResponseable)
+		if ok {
+			assignments[userID], err = c.collectAppRoleAssignments(ctx, userID, page)
+		} else {
+			assignments[userID], err = c.ListUserAppRoleAssignments(userID)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return assignments, nil
+}
+
+func (c *AzureADClient) appRoleAssignmentsRequest(ctx context.Context, userID string) (*abs.RequestInformation, error) {
+	requestInfo, err := c.appClient.UsersById(userID).AppRoleAssignments().ToGetRequestInformation(ctx,
+		&adapproleassignments.AppRoleAssignmentsRequestBuilderGetRequestConfiguration{
+			QueryParameters: &adapproleassignments.AppRoleAssignmentsRequestBuilderGetQueryParameters{
+				Select: appRoleAssignmentFields,
+			},
+		})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to build app role assignments request of user %s: %s", userID, err.Error())
+	}
+	return requestInfo, nil
+}
+
+// collectAppRoleAssignments keeps the assignments of a page, then of the pages following it.
+func (c *AzureADClient) collectAppRoleAssignments(ctx context.Context, userID string,
+	page models.AppRoleAssignmentCollectionResponseable) ([]models.AppRoleAssignmentable, error) {
+	var assignments []models.AppRoleAssignmentable
+	var err error
+	for {
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to list app role assignments of user %s: %s", userID, GraphErrorMessage(err))
+		}
+		if page == nil {
+			return assignments, nil
+		}
+
+		assignments = append(assignments, page.GetValue()...)
+
+		next := page.GetOdataNextLink()
+		if next == nil || *next == "" {
+			return assignments, nil
+		}
+		page, err = adapproleassignments.NewAppRoleAssignmentsRequestBuilder(*next, c.adapter).Get(ctx, nil)
+	}
+}
+
+// GetServicePrincipal returns the service principal with the given object id, including the app roles it exposes.
+// Service principals are cached for the lifetime of the client.
+func (c *AzureADClient) GetServicePrincipal(id string) (models.ServicePrincipalable, error) {
+	if sp, ok := c.principals[id]; ok {
+		return sp, nil
+	}
+	sp, err := c.appClient.ServicePrincipalsById(id).Get(context.Background(),
+		&spitem.ServicePrincipalItemRequestBuilderGetRequestConfiguration{
+			QueryParameters: &spitem.ServicePrincipalItemRequestBuilderGetQueryParameters{
+				Select: servicePrincipalFields,
+			},
+		})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get service principal %s: %s", id, GraphErrorMessage(err))
+	}
+	c.cachePrincipal(sp)
+	return sp, nil
+}
+
+// GetServicePrincipals returns, by object id, the service principals with the given object ids, requesting the
+// ones not cached yet maxInValues at a time. Ids that do not exist are left out.
+func (c *AzureADClient) GetServicePrincipals(ids []string) (map[string]models.ServicePrincipalable, error) {
+	var missing []any
+	requested := make(map[string]bool)
+	for _, id := range ids {
+		if _, ok := c.principals[id]; !ok && !requested[id] {
+			requested[id] = true
+			missing = append(missing, id)
+		}
+	}
+
+	for start := 0; start < len(missing); start += maxInValues {
+		end := start + maxInValues
+		if end > len(missing) {
+			end = len(missing)
+		}
+		filter, err := In("id", missing[start:end]...).Build()
+		if err != nil {
+			return nil, err
+		}
+		page, err := c.appClient.ServicePrincipals().Get(context.Background(),
+			&adserviceprincipals.ServicePrincipalsRequestBuilderGetRequestConfiguration{
+				QueryParameters: &adserviceprincipals.ServicePrincipalsRequestBuilderGetQueryParameters{
+					Filter: &filter,
+					Select: servicePrincipalFields,
+				},
+			})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get service principals: %s", GraphErrorMessage(err))
+		}
+		for _, sp := range page.GetValue() {
+			c.cachePrincipal(sp)
+		}
+	}
+
+	principals := make(map[string]models.ServicePrincipalable, len(ids))
+	for _, id := range ids {
+		if sp, ok := c.principals[id]; ok {
+			principals[id] = sp
+		}
+	}
+	return principals, nil
+}
+
+func (c *AzureADClient) cachePrincipal(sp models.ServicePrincipalable) {
+	if sp.GetId() != nil {
+		c.principals[*sp.GetId()] = sp
+	}
+	if sp.GetAppId() != nil {
+		c.principals[*sp.GetAppId()] = sp
+	}
+}
+
+// ResolveServicePrincipal returns the service principal whose object id or, failing that, app id is ref.
+func (c *AzureADClient) ResolveServicePrincipal(ref string) (models.ServicePrincipalable, error) {
+	ctx := context.Background()
+
+	if _, err := uuid.Parse(ref); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "service principal %q is neither an object id nor an app id", ref)
+	}
+	if sp, ok := c.principals[ref]; ok {
+		return sp, nil
+	}
+
+	sp, err := c.appClient.ServicePrincipalsById(ref).Get(ctx,
+		&spitem.ServicePrincipalItemRequestBuilderGetRequestConfiguration{
+			QueryParameters: &spitem.ServicePrincipalItemRequestBuilderGetQueryParameters{
+				Select: servicePrincipalFields,
+			},
+		})
+	if err == nil {
+		c.cachePrincipal(sp)
+		return sp, nil
+	}
+	if GraphErrorCode(err) != "Request_ResourceNotFound" {
+		return nil, status.Errorf(codes.Internal, "failed to get service principal %s: %s", ref, GraphErrorMessage(err))
+	}
+
+	filter, err := Eq("appId", ref).Build()
+	if err != nil {
+		return nil, err
+	}
+	page, err := c.appClient.ServicePrincipals().Get(ctx,
+		&adserviceprincipals.ServicePrincipalsRequestBuilderGetRequestConfiguration{
+			QueryParameters: &adserviceprincipals.ServicePrincipalsRequestBuilderGetQueryParameters{
+				Filter: &filter,
+				Select: servicePrincipalFields,
+			},
+		})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to look up service principal %s: %s", ref, GraphErrorMessage(err))
+	}
+	if len(page.GetValue()) == 0 {
+		return nil, status.Errorf(codes.NotFound, "service principal %s was not found", ref)
+	}
+	c.cachePrincipal(page.GetValue()[0])
+	return page.GetValue()[0], nil
+}
+
+// FindB2CExtensionsApp returns the service principal of the b2c-extensions-app of an Azure AD B2C tenant.
+func (c *AzureADClient) FindB2CExtensionsApp() (models.ServicePrincipalable, error) {
+	filter, err := StartsWith("displayName", b2cExtensionsAppName).Build()
+	if err != nil {
+		return nil, err
+	}
+	page, err := c.appClient.ServicePrincipals().Get(context.Background(),
+		&adserviceprincipals.ServicePrincipalsRequestBuilderGetRequestConfiguration{
+			QueryParameters: &adserviceprincipals.ServicePrincipalsRequestBuilderGetQueryParameters{
+				Filter: &filter,
+				Select: servicePrincipalFields,
+			},
+		})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to look up the %s: %s", b2cExtensionsAppName, GraphErrorMessage(err))
+	}
+
+	switch len(page.GetValue()) {
+	case 0:
+		return nil, status.Errorf(codes.NotFound, "no %s was found; the tenant is not an Azure AD B2C tenant", b2cExtensionsAppName)
+	case 1:
+		return page.GetValue()[0], nil
+	default:
+		return nil, status.Errorf(codes.FailedPrecondition, "several applications are named %s; please provide its app id", b2cExtensionsAppName)
+	}
+}
+
+// ListAppRoleAssignedTo returns the app role assignments granted to users, groups and service principals
+// on the given resource service principal.
+func (c *AzureADClient) ListAppRoleAssignedTo(servicePrincipalID string) ([]models.AppRoleAssignmentable, error) {
+	ctx := context.Background()
+
+	page, err := c.appClient.ServicePrincipalsById(servicePrincipalID).AppRoleAssignedTo().Get(ctx,
+		&spapproleassignedto.AppRoleAssignedToRequestBuilderGetRequestConfiguration{
+			QueryParameters: &spapproleassignedto.AppRoleAssignedToRequestBuilderGetQueryParameters{
+				Select: assignedToFields,
+			},
+		})
+
+	var assignments []models.AppRoleAssignmentable
+	for {
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to list app role assignments of service principal %s: %s",
+				servicePrincipalID, GraphErrorMessage(err))
+		}
+		if page == nil {
+			return assignments, nil
+		}
+
+		assignments = append(assignments, page.GetValue()...)
+
+		next := page.GetOdataNextLink()
+		if next == nil || *next == "" {
+			return assignments, nil
+		}
+		page, err = spapproleassignedto.NewAppRoleAssignedToRequestBuilder(*next, c.adapter).Get(ctx, nil)
+	}
+}
+
+// ListAppAssignments returns the users assigned to the given enterprise application, directly or through
+// groups. Only the assignments and the ids of group members are read upfront; the users themselves are read
+// as the iterator is walked. Assignments to service principals are skipped.
+func (c *AzureADClient) ListAppAssignments(servicePrincipalID string) (*AppAssignments, error) {
+	ctx := context.Background()
+
+	assignments, err := c.ListAppRoleAssignedTo(servicePrincipalID)
+	if err != nil {
+		return nil, err
+	}
+
+	granted := make(map[string][]models.AppRoleAssignmentable)
+	groups := make(map[string][]models.AppRoleAssignmentable)
+	var groupIDs []string
+
+	for _, assignment := range assignments {
+		if assignment.GetPrincipalId() == nil || assignment.GetPrincipalType() == nil {
+			continue
+		}
+		id := assignment.GetPrincipalId().String()
+		switch *assignment.GetPrincipalType() {
+		case principalTypeUser:
+			granted[id] = append(granted[id], assignment)
+		case principalTypeGroup:
+			if _, ok := groups[id]; !ok {
+				groupIDs = append(groupIDs, id)
+			}
+			groups[id] = append(groups[id], assignment)
+		}
+	}
+
+	// each group is walked on its own, as its members inherit the assignments of that group only
+	for _, groupID := range groupIDs {
+		memberIDs, err := c.listGroupMemberIDs(ctx, groupID)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range memberIDs {
+			granted[id] = append(granted[id], groups[groupID]...)
+		}
+	}
+
+	ids := make([]string, 0, len(granted))
+	for id := range granted {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return &AppAssignments{Users: c.ListUsersByIDPaged(ids), Granted: granted}, nil
+}
@@ -2,106 +2,455 @@ package azureclient
 
 import (
 	"context"
-	"fmt"
+	nethttp "net/http"
+	"slices"
+	"strings"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	azpolicy "github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	msgraphsdk "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph"
 	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/models"
 	adusers "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/users"
+	addelta "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/users/delta"
+	"github.com/google/uuid"
+	abs "github.com/microsoft/kiota-abstractions-go"
 	auth "github.com/microsoft/kiota-authentication-azure-go"
 	http "github.com/microsoft/kiota-http-go"
 )
 
+var userFields = []string{"displayName", "id", "mail", "createdDateTime", "mobilePhone", "userPrincipalName",
+	"accountEnabled", "userType", "isResourceAccount", "creationType", "givenName", "surname",
+	"externalUserState"}
+
 type AzureADClient struct {
 	appClient *msgraphsdk.Msgraph
+	adapter   abs.RequestAdapter
+	fields    []string
+	expand    []string
+	retry     *retryPolicy
+	// principals caches the service principals looked up by the client, by object id and app id.
+	principals map[string]models.ServicePrincipalable
 }
 
-func NewAzureADClient(ctx context.Context, tenant, clientID, clientSecret string) (*AzureADClient, error) {
-	c := &AzureADClient{}
+// Options tunes how the client talks to Graph and to the token endpoints.
+type Options struct {
+	Retry RetryOptions
+	// Cloud selects the Azure AD authority and Graph endpoint; the public cloud is used when empty.
+	Cloud Cloud
+	// HTTPClient sends the Graph and token requests; http.DefaultTransport is used when nil.
+	HTTPClient *nethttp.Client
+}
 
-	credential, err := azidentity.NewClientSecretCredential(tenant, clientID, clientSecret, nil)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create an Azure secret credential: %s", err.Error())
+func NewAzureADClient(ctx context.Context, tenant, clientID, clientSecret string, options *Options) (*AzureADClient, error) {
+	return NewClient(ctx, Credentials{
+		Kind:         CredentialClientSecret,
+		Tenant:       tenant,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+	}, options)
+}
+
+func NewAzureADClientWithRefreshToken(ctx context.Context, tenant, clientID, clientSecret, refreshToken string, options *Options) (*AzureADClient, error) {
+	return NewClient(ctx, Credentials{
+		Kind:         CredentialRefreshToken,
+		Tenant:       tenant,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RefreshToken: refreshToken,
+	}, options)
+}
+
+func (o *Options) retry() RetryOptions {
+	if o == nil {
+		return RetryOptions{}
 	}
+	return o.Retry
+}
 
-	c.appClient, err = getAppClient(credential)
-	if err != nil {
-		return nil, err
+func (o *Options) cloud() Cloud {
+	if o == nil {
+		return PublicCloud
 	}
-	return c, nil
+	return o.Cloud.withDefaults()
 }
 
-func NewAzureADClientWithRefreshToken(ctx context.Context, tenant, clientID, clientSecret, refreshToken string) (*AzureADClient, error) {
-	c := &AzureADClient{}
+func (o *Options) transport() nethttp.RoundTripper {
+	if o == nil || o.HTTPClient == nil || o.HTTPClient.Transport == nil {
+		return nethttp.DefaultTransport
+	}
+	return o.HTTPClient.Transport
+}
 
-	credential, err := NewRefreshTokenCredential(ctx, tenant, clientID, clientSecret, refreshToken)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create Refresh Token credential: %s", err.Error())
+// clientOptions returns the azidentity pipeline options. The token requests are sent through the retry policy
+// of the Graph requests, in place of the azidentity one, so that they follow the same limits and are counted.
+func (o *Options) clientOptions(policy *retryPolicy) azcore.ClientOptions {
+	return azcore.ClientOptions{
+		Cloud:     o.cloud().configuration(),
+		Retry:     azpolicy.RetryOptions{MaxRetries: -1},
+		Transport: &nethttp.Client{Transport: &RetryTransport{policy: policy, next: o.transport()}},
 	}
+}
 
-	c.appClient, err = getAppClient(credential)
+func newClient(credential azcore.TokenCredential, options *Options, policy *retryPolicy) (*AzureADClient, error) {
+	adapter, err := getAdapter(credential, options, policy)
 	if err != nil {
 		return nil, err
 	}
-	return c, nil
+	client := NewAzureADClientWithAdapter(adapter)
+	client.retry = policy
+	return client, nil
+}
+
+// NewAzureADClientWithAdapter creates a client that sends its Graph requests through the given request adapter.
+func NewAzureADClientWithAdapter(adapter abs.RequestAdapter) *AzureADClient {
+	return &AzureADClient{
+		appClient:  msgraphsdk.NewMsgraph(adapter),
+		adapter:    adapter,
+		fields:     userFields,
+		principals: make(map[string]models.ServicePrincipalable),
+	}
+}
+
+// Retries returns the number of throttled or failed requests the client has retried so far.
+func (c *AzureADClient) Retries() int32 {
+	if c.retry == nil {
+		return 0
+	}
+	return c.retry.count()
+}
+
+// SelectUserProperties adds Graph user properties to the $select of the user listing and lookup requests.
+func (c *AzureADClient) SelectUserProperties(properties ...string) {
+	fields := append([]string{}, c.fields...)
+	for _, property := range properties {
+		if !slices.Contains(fields, property) {
+			fields = append(fields, property)
+		}
+	}
+	c.fields = fields
+}
+
+// ExpandUserProperties adds Graph user relationships, such as extensions, to the $expand of the user listing
+// and lookup requests. Delta queries do not support $expand and ignore them.
+func (c *AzureADClient) ExpandUserProperties(properties ...string) {
+	expand := append([]string{}, c.expand...)
+	for _, property := range properties {
+		if !slices.Contains(expand, property) {
+			expand = append(expand, property)
+		}
+	}
+	c.expand = expand
 }
 
+// ListUsers returns the first page of users in the tenant.
 func (c *AzureADClient) ListUsers() (models.UserCollectionResponseable, error) {
-	return c.listUsers("")
+	return c.listUsers(context.Background(), Filter{})
+}
+
+// UserQuery narrows the users listed by ListUsersMatching.
+type UserQuery struct {
+	// Filter is sent as $filter.
+	Filter Filter
+	// Search is sent as $search, for example "displayName:alice" with the double quotes. It makes the request
+	// an advanced query, sent with the ConsistencyLevel: eventual header and $count.
+	Search string
+	// Advanced makes the request an advanced query even without a search, as some filters require.
+	Advanced bool
+}
+
+// advanced reports whether the query needs Graph's advanced query capabilities.
+func (q UserQuery) advanced() bool {
+	return q.Advanced || q.Search != ""
+}
+
+func (q UserQuery) headers() *abs.RequestHeaders {
+	if !q.advanced() {
+		return nil
+	}
+	headers := abs.NewRequestHeaders()
+	headers.Add("ConsistencyLevel", "eventual")
+	return headers
+}
+
+// ListUsersPaged returns an iterator over every page of users in the tenant.
+func (c *AzureADClient) ListUsersPaged() *UserPageIterator {
+	return c.ListUsersMatching(UserQuery{})
+}
+
+// ListUsersMatching returns an iterator over every page of users matching query.
+func (c *AzureADClient) ListUsersMatching(query UserQuery) *UserPageIterator {
+	return &UserPageIterator{
+		first: func(ctx context.Context) (userPage, error) {
+			return c.queryUsers(ctx, query, 0)
+		},
+		next: func(ctx context.Context, link string) (userPage, error) {
+			// advanced queries need their header on every page
+			return adusers.NewUsersRequestBuilder(link, c.adapter).Get(ctx, &adusers.UsersRequestBuilderGetRequestConfiguration{
+				Headers: query.headers(),
+			})
+		},
+	}
+}
+
+// CountUsers returns the number of users matching query, through an advanced query.
+func (c *AzureADClient) CountUsers(query UserQuery) (int64, error) {
+	query.Advanced = true
+	page, err := c.queryUsers(context.Background(), query, 1)
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "failed to count users: %s", GraphErrorMessage(err))
+	}
+	if page == nil || page.GetOdataCount() == nil {
+		return 0, status.Error(codes.Internal, "failed to count users: the response has no @odata.count")
+	}
+	return *page.GetOdataCount(), nil
+}
+
+// ProbeUsers requests a single user matching query, to check that Graph accepts the query.
+func (c *AzureADClient) ProbeUsers(query UserQuery) error {
+	_, err := c.queryUsers(context.Background(), query, 1)
+	return err
+}
+
+// ListUsersDelta returns an iterator over the users changed since deltaLink was issued.
+// An empty deltaLink starts a new delta round that enumerates every user.
+// Users removed from the directory carry an "@removed" entry in their additional data.
+func (c *AzureADClient) ListUsersDelta(deltaLink string) *UserPageIterator {
+	next := func(ctx context.Context, link string) (userPage, error) {
+		return addelta.NewDeltaRequestBuilder(link, c.adapter).Get(ctx, nil)
+	}
+
+	first := func(ctx context.Context) (userPage, error) {
+		if deltaLink != "" {
+			return next(ctx, deltaLink)
+		}
+		return c.appClient.Users().Delta().Get(ctx, &addelta.DeltaRequestBuilderGetRequestConfiguration{
+			QueryParameters: &addelta.DeltaRequestBuilderGetQueryParameters{
+				Select: c.fields,
+			},
+		})
+	}
+
+	return &UserPageIterator{first: first, next: next}
 }
 
 func (c *AzureADClient) GetUserByID(id string) (models.UserCollectionResponseable, error) {
-	filter := fmt.Sprintf("id eq '%s'", id)
-	return c.listUsers(filter)
+	return c.listUsers(context.Background(), Eq("id", id))
 }
 
-func (c *AzureADClient) GetUserByEmail(email string) (models.UserCollectionResponseable, error) {
-	filter := fmt.Sprintf("mail eq '%s'", email)
+// ListUsersByID returns the users with the given object ids, requesting maxInValues of them at a time.
+// Ids that no longer exist are left out.
+func (c *AzureADClient) ListUsersByID(ids []string) ([]models.Userable, error) {
+	ctx := context.Background()
+
+	var users []models.Userable
+	pages := c.ListUsersByIDPaged(ids)
+	for pages.HasNext() {
+		page, err := pages.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, page...)
+	}
+	return users, nil
+}
 
-	aadUsers, err := c.listUsers(filter)
+// ListUsersByIDPaged returns an iterator over the users with the given object ids, whose pages hold the users
+// of maxInValues ids at most. Ids that no longer exist are left out.
+func (c *AzureADClient) ListUsersByIDPaged(ids []string) *UserPageIterator {
+	start := 0
+	return &UserPageIterator{
+		first: func(ctx context.Context) (userPage, error) {
+			if start >= len(ids) {
+				return nil, nil
+			}
+			end := start + maxInValues
+			if end > len(ids) {
+				end = len(ids)
+			}
+			values := make([]any, 0, end-start)
+			for _, id := range ids[start:end] {
+				values = append(values, id)
+			}
+			start = end
+
+			page, err := c.listUsers(ctx, In("id", values...))
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to get users by id: %s", GraphErrorMessage(err))
+			}
+			return page, nil
+		},
+		next: func(ctx context.Context, link string) (userPage, error) {
+			return adusers.NewUsersRequestBuilder(link, c.adapter).Get(ctx, nil)
+		},
+		more: func() bool {
+			return start < len(ids)
+		},
+	}
+}
+
+func (c *AzureADClient) GetUserByEmail(email string) (models.UserCollectionResponseable, error) {
+	aadUsers, err := c.listUsers(context.Background(), Eq("mail", email))
 	if err != nil {
 		return aadUsers, err
 	}
 
 	azureadUsers := aadUsers.GetValue()
 	if len(azureadUsers) < 1 {
-		filter := fmt.Sprintf("userPrincipalName eq '%s'", email)
-		return c.listUsers(filter)
+		return c.listUsers(context.Background(), Eq("userPrincipalName", email))
 	}
 	return aadUsers, err
 }
 
-func (c *AzureADClient) listUsers(filter string) (models.UserCollectionResponseable, error) {
-	query := adusers.UsersRequestBuilderGetQueryParameters{
-		Select: []string{"displayName", "id", "mail", "createdDateTime", "mobilePhone", "userPrincipalName"},
-		Filter: &filter,
+// FindUser looks up an existing user by object id, then mail, then userPrincipalName.
+// It returns nil without an error when no user matches.
+func (c *AzureADClient) FindUser(pid, email, upn string) (models.Userable, error) {
+	if _, err := uuid.Parse(pid); err == nil {
+		aadUsers, err := c.GetUserByID(pid)
+		if err != nil {
+			return nil, err
+		}
+		if users := aadUsers.GetValue(); len(users) > 0 {
+			return users[0], nil
+		}
+	}
+
+	if email != "" {
+		aadUsers, err := c.GetUserByEmail(email)
+		if err != nil {
+			return nil, err
+		}
+		if users := aadUsers.GetValue(); len(users) > 0 {
+			return users[0], nil
+		}
+	}
+
+	if upn != "" && !strings.EqualFold(upn, email) {
+		aadUsers, err := c.listUsers(context.Background(), Eq("userPrincipalName", upn))
+		if err != nil {
+			return nil, err
+		}
+		if users := aadUsers.GetValue(); len(users) > 0 {
+			return users[0], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// CreateUser creates a new user in the tenant.
+func (c *AzureADClient) CreateUser(user models.Userable) (models.Userable, error) {
+	created, err := c.appClient.Users().Post(context.Background(), user, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create user: %s", GraphErrorMessage(err))
+	}
+	return created, nil
+}
+
+// UpdateUser patches the properties of an existing user.
+func (c *AzureADClient) UpdateUser(id string, user models.Userable) error {
+	_, err := c.appClient.UsersById(id).Patch(context.Background(), user, nil)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to update user %s: %s", id, GraphErrorMessage(err))
+	}
+	return nil
+}
+
+// DeleteUser soft deletes a user, moving it to the directory's deleted items.
+func (c *AzureADClient) DeleteUser(id string) error {
+	err := c.appClient.UsersById(id).Delete(context.Background(), nil)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to delete user %s: %s", id, GraphErrorMessage(err))
+	}
+	return nil
+}
+
+// PurgeDeletedUser permanently removes a soft deleted user from the directory's deleted items.
+// It returns a NotFound error if the user is not in the deleted items.
+func (c *AzureADClient) PurgeDeletedUser(id string) error {
+	err := c.appClient.Directory().DeletedItemsById(id).Delete(context.Background(), nil)
+	if isNotFound(err) {
+		return status.Errorf(codes.NotFound, "deleted user %s not found: %s", id, GraphErrorMessage(err))
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to purge deleted user %s: %s", id, GraphErrorMessage(err))
+	}
+	return nil
+}
+
+// RestoreUser restores a soft deleted user from the directory's deleted items.
+// It returns a NotFound error if the user is not in the deleted items.
+func (c *AzureADClient) RestoreUser(id string) error {
+	_, err := c.appClient.Directory().DeletedItemsById(id).Restore().Post(context.Background(), nil)
+	if isNotFound(err) {
+		return status.Errorf(codes.NotFound, "deleted user %s not found: %s", id, GraphErrorMessage(err))
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to restore user %s: %s", id, GraphErrorMessage(err))
+	}
+	return nil
+}
+
+// SetUserEnabled enables or disables sign-in for a user.
+func (c *AzureADClient) SetUserEnabled(id string, enabled bool) error {
+	user := models.NewUser()
+	user.SetAccountEnabled(&enabled)
+	return c.UpdateUser(id, user)
+}
+
+func (c *AzureADClient) listUsers(ctx context.Context, filter Filter) (models.UserCollectionResponseable, error) {
+	return c.queryUsers(ctx, UserQuery{Filter: filter}, 0)
+}
+
+// queryUsers requests the first page of users matching query, of at most top users when top is positive.
+func (c *AzureADClient) queryUsers(ctx context.Context, query UserQuery, top int32) (models.UserCollectionResponseable, error) {
+	params := adusers.UsersRequestBuilderGetQueryParameters{
+		Select: c.fields,
+		Expand: c.expand,
+	}
+	expr, err := query.Filter.Build()
+	if err != nil {
+		return nil, err
+	}
+	if expr != "" {
+		params.Filter = &expr
+	}
+	if query.Search != "" {
+		params.Search = &query.Search
+	}
+	if query.advanced() {
+		count := true
+		params.Count = &count
+	}
+	if top > 0 {
+		params.Top = &top
 	}
 	return c.appClient.Users().
-		Get(context.Background(),
+		Get(ctx,
 			&adusers.UsersRequestBuilderGetRequestConfiguration{
-				QueryParameters: &query,
+				Headers:         query.headers(),
+				QueryParameters: &params,
 			})
 }
 
-func getAppClient(credential azcore.TokenCredential) (*msgraphsdk.Msgraph, error) {
+func getAdapter(credential azcore.TokenCredential, options *Options, policy *retryPolicy) (abs.RequestAdapter, error) {
 	authProvider, err := auth.NewAzureIdentityAuthenticationProviderWithScopes(credential, []string{
-		"https://graph.microsoft.com/.default",
+		options.cloud().Scope(),
 	})
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to create Azure identity provider: %s", err.Error())
 	}
 
 	// Create a request adapter using the auth provider
-	adapter, err := http.NewNetHttpRequestAdapter(authProvider)
+	httpClient := http.GetDefaultClient()
+	httpClient.Transport = http.NewCustomTransportWithParentTransport(options.transport(), graphMiddlewares(policy)...)
+	adapter, err := http.NewNetHttpRequestAdapterWithParseNodeFactoryAndSerializationWriterFactoryAndHttpClient(
+		authProvider, nil, nil, httpClient)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to create Azure AD Graph request adapter: %s", err.Error())
 	}
-
-	// Create a Graph client using request adapter
-	client := msgraphsdk.NewMsgraph(adapter)
-	return client, nil
+	adapter.SetBaseUrl(options.cloud().BaseURL())
+	return adapter, nil
 }
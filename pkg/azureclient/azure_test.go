@@ -0,0 +1,664 @@
+package azureclient_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/azureclient"
+	"github.com/microsoft/kiota-abstractions-go/authentication"
+	kiotahttp "github.com/microsoft/kiota-http-go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestClient(t *testing.T, handler http.Handler) (*azureclient.AzureADClient, string) {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	adapter, err := kiotahttp.NewNetHttpRequestAdapter(&authentication.AnonymousAuthenticationProvider{})
+	require.NoError(t, err)
+	adapter.SetBaseUrl(server.URL)
+
+	return azureclient.NewAzureADClientWithAdapter(adapter), server.URL
+}
+
+func TestListUsersPagedFollowsNextLink(t *testing.T) {
+	assert := require.New(t)
+
+	var serverURL string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("$skiptoken") == "page2" {
+			fmt.Fprint(w, `{"value":[{"id":"3","displayName":"Three"}]}`)
+			return
+		}
+		fmt.Fprintf(w, `{"@odata.nextLink":"%s/users?$skiptoken=page2","value":[{"id":"1"},{"id":"2"}]}`, serverURL)
+	})
+	var client *azureclient.AzureADClient
+	client, serverURL = newTestClient(t, mux)
+
+	pages := client.ListUsersPaged()
+	assert.True(pages.HasNext())
+
+	first, err := pages.Next(context.Background())
+	assert.NoError(err)
+	assert.Len(first, 2)
+	assert.True(pages.HasNext())
+
+	second, err := pages.Next(context.Background())
+	assert.NoError(err)
+	assert.Len(second, 1)
+	assert.Equal("Three", *second[0].GetDisplayName())
+	assert.False(pages.HasNext())
+
+	_, err = pages.Next(context.Background())
+	assert.Equal(io.EOF, err)
+}
+
+func TestListUsersPagedError(t *testing.T) {
+	assert := require.New(t)
+
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"error":{"code":"Authorization_RequestDenied","message":"denied"}}`)
+	}))
+
+	_, err := client.ListUsersPaged().Next(context.Background())
+	assert.Error(err)
+}
+
+func TestListUsersMatchingSearch(t *testing.T) {
+	assert := require.New(t)
+
+	var serverURL string
+	var queries []url.Values
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("eventual", r.Header.Get("ConsistencyLevel"))
+		queries = append(queries, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("$skiptoken") == "page2" {
+			fmt.Fprint(w, `{"value":[{"id":"2"}]}`)
+			return
+		}
+		fmt.Fprintf(w, `{"@odata.count":2,"@odata.nextLink":"%s/users?$skiptoken=page2","value":[{"id":"1"}]}`, serverURL)
+	})
+	var client *azureclient.AzureADClient
+	client, serverURL = newTestClient(t, mux)
+
+	pages := client.ListUsersMatching(azureclient.UserQuery{
+		Filter: azureclient.Raw("accountEnabled eq true"),
+		Search: `"displayName:alice"`,
+	})
+	for pages.HasNext() {
+		_, err := pages.Next(context.Background())
+		assert.NoError(err)
+	}
+
+	assert.Len(queries, 2)
+	assert.Equal("accountEnabled eq true", queries[0].Get("$filter"))
+	assert.Equal(`"displayName:alice"`, queries[0].Get("$search"))
+	assert.Equal("true", queries[0].Get("$count"))
+}
+
+func TestProbeUsers(t *testing.T) {
+	assert := require.New(t)
+
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("1", r.URL.Query().Get("$top"))
+		assert.Empty(r.Header.Get("ConsistencyLevel"))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":{"code":"Request_UnsupportedQuery","message":"Unsupported query."}}`)
+	}))
+
+	err := client.ProbeUsers(azureclient.UserQuery{Filter: azureclient.Raw("accountEnabled eq maybe")})
+	assert.Error(err)
+	assert.Equal("Request_UnsupportedQuery", azureclient.GraphErrorCode(err))
+}
+
+func TestCountUsers(t *testing.T) {
+	assert := require.New(t)
+
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("eventual", r.Header.Get("ConsistencyLevel"))
+		assert.Equal("true", r.URL.Query().Get("$count"))
+		assert.Equal("accountEnabled eq false", r.URL.Query().Get("$filter"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"@odata.count":42,"value":[{"id":"1"}]}`)
+	}))
+
+	count, err := client.CountUsers(azureclient.UserQuery{Filter: azureclient.Eq("accountEnabled", false)})
+	assert.NoError(err)
+	assert.Equal(int64(42), count)
+}
+
+func TestFindUserFallsBackToUPN(t *testing.T) {
+	assert := require.New(t)
+
+	var filters []string
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter := r.URL.Query().Get("$filter")
+		filters = append(filters, filter)
+		w.Header().Set("Content-Type", "application/json")
+		if filter == "userPrincipalName eq 'name@tenant.onmicrosoft.com'" {
+			fmt.Fprint(w, `{"value":[{"id":"42"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"value":[]}`)
+	}))
+
+	user, err := client.FindUser("not-a-guid", "name@test.com", "name@tenant.onmicrosoft.com")
+	assert.NoError(err)
+	assert.NotNil(user)
+	assert.Equal("42", *user.GetId())
+	assert.Equal([]string{
+		"mail eq 'name@test.com'",
+		"userPrincipalName eq 'name@test.com'",
+		"userPrincipalName eq 'name@tenant.onmicrosoft.com'",
+	}, filters)
+}
+
+func TestDeletePurgeAndRestoreUser(t *testing.T) {
+	assert := require.New(t)
+
+	var requests []string
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPost {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"@odata.type":"#microsoft.graph.user","id":"42"}`)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	assert.NoError(client.DeleteUser("42"))
+	assert.NoError(client.PurgeDeletedUser("42"))
+	assert.NoError(client.RestoreUser("42"))
+	assert.Equal([]string{
+		"DELETE /users/42",
+		"DELETE /directory/deletedItems/42",
+		"POST /directory/deletedItems/42/microsoft.graph.restore",
+	}, requests)
+}
+
+func TestRestoreAndPurgeMissingDeletedUser(t *testing.T) {
+	assert := require.New(t)
+
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"code":"Request_ResourceNotFound","message":"Resource '42' does not exist."}}`)
+	}))
+
+	assert.Equal(codes.NotFound, status.Code(client.RestoreUser("42")))
+	assert.Equal(codes.NotFound, status.Code(client.PurgeDeletedUser("42")))
+}
+
+func TestListUsersDelta(t *testing.T) {
+	assert := require.New(t)
+
+	var serverURL string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/microsoft.graph.delta()", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Query().Get("$deltatoken") != "":
+			fmt.Fprintf(w, `{"@odata.deltaLink":"%s/users/microsoft.graph.delta()?$deltatoken=t2","value":[{"id":"2","@removed":{"reason":"deleted"}}]}`, serverURL)
+		case r.URL.Query().Get("$skiptoken") != "":
+			fmt.Fprintf(w, `{"@odata.deltaLink":"%s/users/microsoft.graph.delta()?$deltatoken=t1","value":[{"id":"2"}]}`, serverURL)
+		default:
+			fmt.Fprintf(w, `{"@odata.nextLink":"%s/users/microsoft.graph.delta()?$skiptoken=s1","value":[{"id":"1"}]}`, serverURL)
+		}
+	})
+	var client *azureclient.AzureADClient
+	client, serverURL = newTestClient(t, mux)
+
+	pages := client.ListUsersDelta("")
+	users, err := pages.Next(context.Background())
+	assert.NoError(err)
+	assert.Len(users, 1)
+	assert.Empty(pages.DeltaLink())
+
+	users, err = pages.Next(context.Background())
+	assert.NoError(err)
+	assert.Len(users, 1)
+	assert.False(azureclient.IsRemoved(users[0]))
+	assert.False(pages.HasNext())
+	assert.Equal(serverURL+"/users/microsoft.graph.delta()?$deltatoken=t1", pages.DeltaLink())
+
+	pages = client.ListUsersDelta(pages.DeltaLink())
+	users, err = pages.Next(context.Background())
+	assert.NoError(err)
+	assert.Len(users, 1)
+	assert.True(azureclient.IsRemoved(users[0]))
+	assert.Equal(serverURL+"/users/microsoft.graph.delta()?$deltatoken=t2", pages.DeltaLink())
+}
+
+func TestListUserGroups(t *testing.T) {
+	assert := require.New(t)
+
+	var serverURL string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/1/transitiveMemberOf", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("$skiptoken") == "page2" {
+			fmt.Fprint(w, `{"value":[{"@odata.type":"#microsoft.graph.group","id":"g2","displayName":"Two"}]}`)
+			return
+		}
+		fmt.Fprintf(w, `{"@odata.nextLink":"%s/users/1/transitiveMemberOf?$skiptoken=page2","value":[`+
+			`{"@odata.type":"#microsoft.graph.group","id":"g1","displayName":"One"},`+
+			`{"@odata.type":"#microsoft.graph.directoryRole","id":"r1","displayName":"Global Reader"}]}`, serverURL)
+	})
+	var client *azureclient.AzureADClient
+	client, serverURL = newTestClient(t, mux)
+
+	groups, err := client.ListUserGroups("1", true)
+	assert.NoError(err)
+	assert.Len(groups, 2)
+	assert.Equal("One", *groups[0].GetDisplayName())
+	assert.Equal("Two", *groups[1].GetDisplayName())
+}
+
+func TestAppRoleAssignmentsAndServicePrincipal(t *testing.T) {
+	assert := require.New(t)
+
+	const resourceID = "7b1d2c4e-0000-4000-8000-000000000001"
+	const roleID = "7b1d2c4e-0000-4000-8000-000000000002"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/1/appRoleAssignments", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"value":[{"id":"a1","appRoleId":"%s","resourceId":"%s","resourceDisplayName":"Expenses"}]}`, roleID, resourceID)
+	})
+	mux.HandleFunc("/servicePrincipals/"+resourceID, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":"%s","appId":"app","appRoles":[{"id":"%s","value":"Approver","isEnabled":true}]}`, resourceID, roleID)
+	})
+	client, _ := newTestClient(t, mux)
+
+	assignments, err := client.ListUserAppRoleAssignments("1")
+	assert.NoError(err)
+	assert.Len(assignments, 1)
+	assert.Equal(resourceID, assignments[0].GetResourceId().String())
+
+	sp, err := client.GetServicePrincipal(resourceID)
+	assert.NoError(err)
+	assert.Len(sp.GetAppRoles(), 1)
+	assert.Equal("Approver", *sp.GetAppRoles()[0].GetValue())
+	assert.Equal(roleID, sp.GetAppRoles()[0].GetId().String())
+}
+
+func TestResolveGroup(t *testing.T) {
+	assert := require.New(t)
+
+	groupID := "6a9d4d24-0b89-4f7b-9c3e-7b1f5e1f2d3a"
+	mux := http.NewServeMux()
+	mux.HandleFunc("/groups/"+groupID, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":"%s","displayName":"Aserto-Users"}`, groupID)
+	})
+	mux.HandleFunc("/groups", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("$filter") {
+		case "displayName eq 'Aserto-Users'":
+			fmt.Fprintf(w, `{"value":[{"id":"%s","displayName":"Aserto-Users"}]}`, groupID)
+		case "displayName eq 'Admins'":
+			fmt.Fprint(w, `{"value":[{"id":"1","displayName":"Admins"},{"id":"2","displayName":"Admins"}]}`)
+		default:
+			fmt.Fprint(w, `{"value":[]}`)
+		}
+	})
+	client, _ := newTestClient(t, mux)
+
+	group, err := client.ResolveGroup(groupID)
+	assert.NoError(err)
+	assert.Equal("Aserto-Users", *group.GetDisplayName())
+
+	group, err = client.ResolveGroup("Aserto-Users")
+	assert.NoError(err)
+	assert.Equal(groupID, *group.GetId())
+
+	_, err = client.ResolveGroup("Admins")
+	assert.Error(err)
+	assert.Contains(err.Error(), "2 groups are named Admins")
+
+	_, err = client.ResolveGroup("Nobody")
+	assert.Error(err)
+	assert.Contains(err.Error(), "was not found")
+}
+
+func TestListGroupMembersPaged(t *testing.T) {
+	assert := require.New(t)
+
+	var serverURL string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/groups/a/transitiveMembers/microsoft.graph.user", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("$skiptoken") == "page2" {
+			fmt.Fprint(w, `{"value":[{"id":"2"}]}`)
+			return
+		}
+		assert.Contains(r.URL.Query().Get("$select"), "userPrincipalName")
+		fmt.Fprintf(w, `{"@odata.nextLink":"%s/groups/a/transitiveMembers/microsoft.graph.user?$skiptoken=page2","value":[{"id":"1"}]}`, serverURL)
+	})
+	mux.HandleFunc("/groups/b/transitiveMembers/microsoft.graph.user", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"value":[{"id":"2"},{"id":"3"}]}`)
+	})
+	var client *azureclient.AzureADClient
+	client, serverURL = newTestClient(t, mux)
+
+	var ids []string
+	pages := client.ListGroupMembersPaged([]string{"a", "b"})
+	for pages.HasNext() {
+		users, err := pages.Next(context.Background())
+		assert.NoError(err)
+		for _, user := range users {
+			ids = append(ids, *user.GetId())
+		}
+	}
+
+	assert.Equal([]string{"1", "2", "3"}, ids)
+}
+
+func TestResolveServicePrincipalByAppID(t *testing.T) {
+	assert := require.New(t)
+
+	const appID = "7b1d2c4e-0000-4000-8000-0000000000aa"
+	mux := http.NewServeMux()
+	mux.HandleFunc("/servicePrincipals/"+appID, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"code":"Request_ResourceNotFound","message":"not found"}}`)
+	})
+	mux.HandleFunc("/servicePrincipals", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("appId eq '"+appID+"'", r.URL.Query().Get("$filter"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"value":[{"id":"sp","appId":"%s","displayName":"Aserto"}]}`, appID)
+	})
+	client, _ := newTestClient(t, mux)
+
+	sp, err := client.ResolveServicePrincipal(appID)
+	assert.NoError(err)
+	assert.Equal("sp", *sp.GetId())
+
+	_, err = client.ResolveServicePrincipal("Aserto")
+	assert.Error(err)
+}
+
+func TestFindB2CExtensionsApp(t *testing.T) {
+	assert := require.New(t)
+
+	apps := `[{"id":"sp","appId":"5c4b3a29-1e8d-4f6a-b1c2-d3e4f5a6b7c8","displayName":"b2c-extensions-app. Do not modify. Used by AADB2C for storing user data."}]`
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("startswith(displayName,'b2c-extensions-app')", r.URL.Query().Get("$filter"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"value":%s}`, apps)
+	}))
+
+	app, err := client.FindB2CExtensionsApp()
+	assert.NoError(err)
+	assert.Equal("5c4b3a29-1e8d-4f6a-b1c2-d3e4f5a6b7c8", *app.GetAppId())
+
+	apps = `[]`
+	_, err = client.FindB2CExtensionsApp()
+	assert.Error(err)
+	assert.Equal(codes.NotFound, status.Code(err))
+}
+
+func TestListAppAssignments(t *testing.T) {
+	assert := require.New(t)
+
+	const (
+		spID    = "7b1d2c4e-0000-4000-8000-000000000001"
+		user1   = "7b1d2c4e-0000-4000-8000-000000000011"
+		user2   = "7b1d2c4e-0000-4000-8000-000000000012"
+		user3   = "7b1d2c4e-0000-4000-8000-000000000013"
+		groupID = "7b1d2c4e-0000-4000-8000-000000000021"
+		appID   = "7b1d2c4e-0000-4000-8000-000000000031"
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/servicePrincipals/"+spID+"/appRoleAssignedTo", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"value":[
+			{"id":"a1","principalId":"%s","principalType":"User","resourceId":"%s"},
+			{"id":"a2","principalId":"%s","principalType":"Group","resourceId":"%s"},
+			{"id":"a3","principalId":"%s","principalType":"User","resourceId":"%s"},
+			{"id":"a4","principalId":"%s","principalType":"ServicePrincipal","resourceId":"%s"}
+		]}`, user1, spID, groupID, spID, user3, spID, appID, spID)
+	})
+	mux.HandleFunc("/groups/"+groupID+"/transitiveMembers/microsoft.graph.user", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("id", r.URL.Query().Get("$select"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"value":[{"id":"%s"},{"id":"%s"}]}`, user1, user2)
+	})
+	var filters []string
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		filters = append(filters, r.URL.Query().Get("$filter"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"value":[{"id":"%s"},{"id":"%s"},{"id":"%s"}]}`, user1, user2, user3)
+	})
+	client, _ := newTestClient(t, mux)
+
+	assignments, err := client.ListAppAssignments(spID)
+	assert.NoError(err)
+	assert.Empty(filters, "users are read as the iterator is walked")
+
+	granted := make(map[string][]string)
+	for id, userAssignments := range assignments.Granted {
+		for _, a := range userAssignments {
+			granted[id] = append(granted[id], *a.GetId())
+		}
+	}
+	assert.Len(granted, 3)
+	assert.ElementsMatch([]string{"a1", "a2"}, granted[user1])
+	assert.Equal([]string{"a2"}, granted[user2])
+	assert.Equal([]string{"a3"}, granted[user3])
+
+	var users []string
+	for assignments.Users.HasNext() {
+		page, err := assignments.Users.Next(context.Background())
+		assert.NoError(err)
+		for _, user := range page {
+			users = append(users, *user.GetId())
+		}
+	}
+	assert.Equal([]string{user1, user2, user3}, users)
+	assert.Equal([]string{"id in ('" + user1 + "','" + user2 + "','" + user3 + "')"}, filters)
+}
+
+func TestGetUserPhoto(t *testing.T) {
+	assert := require.New(t)
+
+	jpeg := []byte{0xff, 0xd8, 0xff, 0xe0, 0x00, 0x10, 'J', 'F', 'I', 'F', 0x00}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/1/photos/240x240/$value", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, _ = w.Write(jpeg)
+	})
+	mux.HandleFunc("/users/2/photos/240x240/$value", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"code":"ImageNotFound","message":"Exception of type 'Microsoft.Fast.Profile.Core.Exception.ImageNotFoundException' was thrown."}}`)
+	})
+	mux.HandleFunc("/users/3/photos/240x240/$value", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	client, _ := newTestClient(t, mux)
+
+	photo, err := client.GetUserPhoto(context.Background(), "1", "240x240")
+	assert.NoError(err)
+	assert.Equal(jpeg, photo.Content)
+	assert.Equal("image/jpeg", photo.ContentType)
+
+	for _, id := range []string{"2", "3"} {
+		photo, err = client.GetUserPhoto(context.Background(), id, "240x240")
+		assert.NoError(err)
+		assert.Nil(photo)
+	}
+}
+
+func TestListUserExtensionProperties(t *testing.T) {
+	assert := require.New(t)
+
+	const appID = "b7b1c57b-532f-40b8-b5ed-4b7a7ba67401"
+	var serverURL string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/applications/"+appID, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"code":"Request_ResourceNotFound","message":"not found"}}`)
+	})
+	mux.HandleFunc("/applications", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("appId eq '"+appID+"'", r.URL.Query().Get("$filter"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"value":[{"id":"app","appId":"%s","displayName":"Extensions"}]}`, appID)
+	})
+	mux.HandleFunc("/applications/app/extensionProperties", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("$skiptoken") == "page2" {
+			fmt.Fprint(w, `{"value":[{"id":"3","name":"extension_b7b1c57b532f40b8b5ed4b7a7ba67401_skills","dataType":"String","isMultiValued":true,"targetObjects":["User","Group"]}]}`)
+			return
+		}
+		fmt.Fprintf(w, `{"@odata.nextLink":"%s/applications/app/extensionProperties?$skiptoken=page2","value":[
+			{"id":"1","name":"extension_b7b1c57b532f40b8b5ed4b7a7ba67401_costCenter","dataType":"String","targetObjects":["User"]},
+			{"id":"2","name":"extension_b7b1c57b532f40b8b5ed4b7a7ba67401_site","dataType":"String","targetObjects":["Device"]}
+		]}`, serverURL)
+	})
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("extensions", r.URL.Query().Get("$expand"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"value":[]}`)
+	})
+	var client *azureclient.AzureADClient
+	client, serverURL = newTestClient(t, mux)
+
+	properties, err := client.ListUserExtensionProperties(appID)
+	assert.NoError(err)
+	assert.Len(properties, 2)
+	assert.Equal("extension_b7b1c57b532f40b8b5ed4b7a7ba67401_costCenter", *properties[0].GetName())
+	assert.True(*properties[1].GetIsMultiValued())
+
+	client.ExpandUserProperties("extensions")
+	_, err = client.ListUsers()
+	assert.NoError(err)
+
+	_, err = client.ListUserExtensionProperties("Extensions")
+	assert.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func TestListUsersByIDBatchesIDs(t *testing.T) {
+	assert := require.New(t)
+
+	var filters []string
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filters = append(filters, r.URL.Query().Get("$filter"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"value":[{"id":"1"}]}`)
+	}))
+
+	ids := make([]string, 16)
+	for i := range ids {
+		ids[i] = fmt.Sprint(i + 1)
+	}
+	users, err := client.ListUsersByID(ids)
+	assert.NoError(err)
+	assert.Len(users, 2)
+	assert.Len(filters, 2)
+	assert.Equal("id in ('16')", filters[1])
+}
+
+func TestListUsersGroupsBatchesRequests(t *testing.T) {
+	assert := require.New(t)
+
+	var batched []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/$batch", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(http.MethodPost, r.Method)
+		var body struct {
+			Requests []struct {
+				ID  string `json:"id"`
+				URL string `json:"url"`
+			} `json:"requests"`
+		}
+		var reader io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			assert.NoError(err)
+			reader = gz
+		}
+		assert.NoError(json.NewDecoder(reader).Decode(&body))
+		assert.Len(body.Requests, 2)
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"responses":[`)
+		for i, request := range body.Requests {
+			batched = append(batched, request.URL)
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			if request.ID == "1" {
+				fmt.Fprintf(w, `{"id":"%s","status":429,"body":{"error":{"code":"TooManyRequests"}}}`, request.ID)
+				continue
+			}
+			fmt.Fprintf(w, `{"id":"%s","status":200,"body":{"value":[`+
+				`{"@odata.type":"#microsoft.graph.group","id":"g1","displayName":"One"}]}}`, request.ID)
+		}
+		fmt.Fprint(w, `]}`)
+	})
+	mux.HandleFunc("/users/2/transitiveMemberOf", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"value":[{"@odata.type":"#microsoft.graph.group","id":"g2","displayName":"Two"}]}`)
+	})
+	client, _ := newTestClient(t, mux)
+
+	groups, err := client.ListUsersGroups([]string{"1", "2"}, true)
+	assert.NoError(err)
+	assert.Len(batched, 2)
+	assert.True(strings.HasPrefix(batched[0], "/users/1/transitiveMemberOf?"), batched[0])
+	assert.Len(groups["1"], 1)
+	assert.Equal("One", *groups["1"][0].GetDisplayName())
+	assert.Len(groups["2"], 1)
+	assert.Equal("Two", *groups["2"][0].GetDisplayName())
+}
+
+func TestGetServicePrincipalsCachesPrincipals(t *testing.T) {
+	assert := require.New(t)
+
+	var filters []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/servicePrincipals", func(w http.ResponseWriter, r *http.Request) {
+		filters = append(filters, r.URL.Query().Get("$filter"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"value":[{"id":"sp1","appId":"app1"},{"id":"sp2","appId":"app2"}]}`)
+	})
+	client, _ := newTestClient(t, mux)
+
+	principals, err := client.GetServicePrincipals([]string{"sp1", "sp2", "sp1", "sp3"})
+	assert.NoError(err)
+	assert.Len(principals, 2)
+	assert.Equal([]string{"id in ('sp1','sp2','sp3')"}, filters)
+
+	principals, err = client.GetServicePrincipals([]string{"sp2"})
+	assert.NoError(err)
+	assert.Equal("app2", *principals["sp2"].GetAppId())
+
+	sp, err := client.GetServicePrincipal("sp1")
+	assert.NoError(err)
+	assert.Equal("app1", *sp.GetAppId())
+	assert.Len(filters, 1)
+}
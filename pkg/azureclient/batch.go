@@ -0,0 +1,115 @@
+package azureclient
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/models/odataerrors"
+	abs "github.com/microsoft/kiota-abstractions-go"
+	"github.com/microsoft/kiota-abstractions-go/serialization"
+	jsonserialization "github.com/microsoft/kiota-serialization-json-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// graphErrorMapping parses the error responses of Graph, as the generated request builders do.
+var graphErrorMapping = abs.ErrorMappings{
+	"4XX": odataerrors.CreateODataErrorFromDiscriminatorValue,
+	"5XX": odataerrors.CreateODataErrorFromDiscriminatorValue,
+}
+
+// maxBatchRequests is the largest number of requests Graph accepts in a single $batch request.
+const maxBatchRequests = 20
+
+type batchRequest struct {
+	ID     string `json:"id"`
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+type batchResponse struct {
+	ID     string          `json:"id"`
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body"`
+}
+
+// batchGet sends GET requests through $batch, maxBatchRequests at a time, and parses the body of each successful
+// response with factory. The results are indexed like requests; the ones whose response failed, for example
+// because it was throttled, are left nil for the caller to send again on their own, through the retry policy.
+func (c *AzureADClient) batchGet(ctx context.Context, requests []*abs.RequestInformation,
+	factory serialization.ParsableFactory) ([]serialization.Parsable, error) {
+	results := make([]serialization.Parsable, len(requests))
+	base := strings.TrimRight(c.adapter.GetBaseUrl(), "/")
+
+	for start := 0; start < len(requests); start += maxBatchRequests {
+		end := start + maxBatchRequests
+		if end > len(requests) {
+			end = len(requests)
+		}
+
+		batch := make([]batchRequest, 0, end-start)
+		for i := start; i < end; i++ {
+			uri, err := requests[i].GetUri()
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to build batch request: %s", err.Error())
+			}
+			batch = append(batch, batchRequest{
+				ID:     strconv.Itoa(i),
+				Method: abs.GET.String(),
+				URL:    strings.TrimPrefix(uri.String(), base),
+			})
+		}
+
+		responses, err := c.sendBatch(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		for _, response := range responses {
+			i, err := strconv.Atoi(response.ID)
+			if err != nil || i < start || i >= end || response.Status < 200 || response.Status > 299 {
+				continue
+			}
+			node, err := jsonserialization.NewJsonParseNode(response.Body)
+			if err != nil {
+				continue
+			}
+			if results[i], err = node.GetObjectValue(factory); err != nil {
+				results[i] = nil
+			}
+		}
+	}
+	return results, nil
+}
+
+func (c *AzureADClient) sendBatch(ctx context.Context, batch []batchRequest) ([]batchResponse, error) {
+	content, err := json.Marshal(struct {
+		Requests []batchRequest `json:"requests"`
+	}{Requests: batch})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to build batch request: %s", err.Error())
+	}
+
+	requestInfo := abs.NewRequestInformation()
+	requestInfo.Method = abs.POST
+	requestInfo.UrlTemplate = "{+baseurl}/$batch"
+	requestInfo.PathParameters = map[string]string{"baseurl": c.adapter.GetBaseUrl()}
+	requestInfo.Headers.Add("Accept", "application/json")
+	requestInfo.Headers.Add("Content-Type", "application/json")
+	requestInfo.Content = content
+
+	res, err := c.adapter.SendPrimitive(ctx, requestInfo, "[]byte", graphErrorMapping)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to send batch request: %s", GraphErrorMessage(err))
+	}
+	body, _ := res.([]byte)
+
+	var responses struct {
+		Responses []batchResponse `json:"responses"`
+	}
+	if err := json.Unmarshal(body, &responses); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to parse batch response: %s", err.Error())
+	}
+	return responses.Responses, nil
+}
@@ -0,0 +1,50 @@
+package azureclient
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// LoadCertificate returns the certificate data held by value, which is either inline PEM, a base64 encoded PFX,
+// or the path of a PEM or PFX file.
+func LoadCertificate(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, status.Error(codes.InvalidArgument, "no client certificate was provided")
+	}
+
+	if data, err := os.ReadFile(value); err == nil {
+		return data, nil
+	} else if !os.IsNotExist(err) {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to read client certificate %s: %s", value, err.Error())
+	}
+
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "client certificate is neither PEM, base64 PFX nor an existing file")
+	}
+	return data, nil
+}
+
+// NewAzureADClientWithCertificate creates a client authenticating as the application with a certificate
+// instead of a client secret. When sendChain is set, the certificate chain is sent in the x5c header of token
+// requests, as required by subject name and issuer authentication.
+func NewAzureADClientWithCertificate(tenant, clientID, certificate, password string, sendChain bool, options *Options) (*AzureADClient, error) {
+	return NewClient(context.Background(), Credentials{
+		Kind:                 CredentialCertificate,
+		Tenant:               tenant,
+		ClientID:             clientID,
+		Certificate:          certificate,
+		CertificatePassword:  password,
+		SendCertificateChain: sendChain,
+	}, options)
+}
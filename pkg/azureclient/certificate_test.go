@@ -0,0 +1,70 @@
+package azureclient_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/azureclient"
+	"github.com/stretchr/testify/require"
+)
+
+func selfSignedPEM(t *testing.T) string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "aserto-idp-plugin-azuread"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})) +
+		string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestLoadCertificate(t *testing.T) {
+	assert := require.New(t)
+	certificate := selfSignedPEM(t)
+
+	data, err := azureclient.LoadCertificate(certificate)
+	assert.NoError(err)
+	assert.Equal(certificate, string(data))
+
+	path := filepath.Join(t.TempDir(), "client.pem")
+	assert.NoError(os.WriteFile(path, []byte(certificate), 0o600))
+	data, err = azureclient.LoadCertificate(path)
+	assert.NoError(err)
+	assert.Equal(certificate, string(data))
+
+	data, err = azureclient.LoadCertificate(base64.StdEncoding.EncodeToString([]byte("pfx")))
+	assert.NoError(err)
+	assert.Equal("pfx", string(data))
+
+	_, err = azureclient.LoadCertificate("missing.pem")
+	assert.Error(err)
+}
+
+func TestNewAzureADClientWithCertificate(t *testing.T) {
+	assert := require.New(t)
+
+	client, err := azureclient.NewAzureADClientWithCertificate("tenant", "id", selfSignedPEM(t), "", true, nil)
+	assert.NoError(err)
+	assert.NotNil(client)
+
+	_, err = azureclient.NewAzureADClientWithCertificate("tenant", "id", "-----BEGIN CERTIFICATE-----\n-----END CERTIFICATE-----", "", false, nil)
+	assert.Error(err)
+}
@@ -0,0 +1,103 @@
+package azureclient
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Cloud names the national clouds Azure AD and Graph are deployed in.
+const (
+	CloudPublic = "public"
+	CloudUSGov  = "usgov"
+	CloudChina  = "china"
+	CloudCustom = "custom"
+)
+
+// Cloud holds the endpoints of the Azure AD cloud the client talks to.
+type Cloud struct {
+	// AuthorityHost is the Azure AD login endpoint, such as https://login.microsoftonline.com.
+	AuthorityHost string
+	// GraphEndpoint is the Graph service root, without the API version, such as https://graph.microsoft.com.
+	GraphEndpoint string
+}
+
+var (
+	// PublicCloud is the global Azure cloud.
+	PublicCloud = Cloud{AuthorityHost: "https://login.microsoftonline.com", GraphEndpoint: "https://graph.microsoft.com"}
+	// USGovCloud is Azure US Government.
+	USGovCloud = Cloud{AuthorityHost: "https://login.microsoftonline.us", GraphEndpoint: "https://graph.microsoft.us"}
+	// ChinaCloud is Azure China, operated by 21Vianet.
+	ChinaCloud = Cloud{AuthorityHost: "https://login.chinacloudapi.cn", GraphEndpoint: "https://microsoftgraph.chinacloudapi.cn"}
+)
+
+// LookupCloud returns the endpoints of a named cloud. The custom cloud takes its endpoints from
+// authorityHost and graphEndpoint, which must both be https URLs; an empty name is the public cloud.
+func LookupCloud(name, authorityHost, graphEndpoint string) (Cloud, error) {
+	switch strings.ToLower(name) {
+	case "", CloudPublic:
+		return PublicCloud, nil
+	case CloudUSGov:
+		return USGovCloud, nil
+	case CloudChina:
+		return ChinaCloud, nil
+	case CloudCustom:
+		c := Cloud{AuthorityHost: strings.TrimRight(authorityHost, "/"), GraphEndpoint: strings.TrimRight(graphEndpoint, "/")}
+		if err := validateEndpoint("authority host", c.AuthorityHost); err != nil {
+			return Cloud{}, err
+		}
+		if err := validateEndpoint("graph endpoint", c.GraphEndpoint); err != nil {
+			return Cloud{}, err
+		}
+		return c, nil
+	default:
+		return Cloud{}, status.Errorf(codes.InvalidArgument, "invalid cloud %q; expected %s, %s, %s or %s",
+			name, CloudPublic, CloudUSGov, CloudChina, CloudCustom)
+	}
+}
+
+func validateEndpoint(name, endpoint string) error {
+	if endpoint == "" {
+		return status.Errorf(codes.InvalidArgument, "no %s was provided for the custom cloud", name)
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return status.Errorf(codes.InvalidArgument, "the %s %q must be an https URL", name, endpoint)
+	}
+	return nil
+}
+
+func (c Cloud) withDefaults() Cloud {
+	if c.AuthorityHost == "" {
+		c.AuthorityHost = PublicCloud.AuthorityHost
+	}
+	if c.GraphEndpoint == "" {
+		c.GraphEndpoint = PublicCloud.GraphEndpoint
+	}
+	return c
+}
+
+// BaseURL returns the Graph v1.0 API root.
+func (c Cloud) BaseURL() string {
+	return strings.TrimRight(c.withDefaults().GraphEndpoint, "/") + "/v1.0"
+}
+
+// Scope returns the token scope granting the application permissions on Graph.
+func (c Cloud) Scope() string {
+	return strings.TrimRight(c.withDefaults().GraphEndpoint, "/") + "/.default"
+}
+
+// TokenURL returns the OAuth2 v2.0 token endpoint of a tenant.
+func (c Cloud) TokenURL(tenant string) string {
+	return strings.TrimRight(c.withDefaults().AuthorityHost, "/") + "/" + tenant + "/oauth2/v2.0/token"
+}
+
+func (c Cloud) configuration() cloud.Configuration {
+	return cloud.Configuration{
+		ActiveDirectoryAuthorityHost: strings.TrimRight(c.withDefaults().AuthorityHost, "/") + "/",
+		Services:                     map[cloud.ServiceName]cloud.ServiceConfiguration{},
+	}
+}
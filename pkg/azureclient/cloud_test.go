@@ -0,0 +1,104 @@
+package azureclient_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/azureclient"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupCloud(t *testing.T) {
+	tests := []struct {
+		name          string
+		cloud         string
+		authorityHost string
+		graphEndpoint string
+		want          azureclient.Cloud
+		err           string
+	}{
+		{name: "default", want: azureclient.PublicCloud},
+		{name: "public", cloud: "public", want: azureclient.PublicCloud},
+		{name: "usgov", cloud: "usgov", want: azureclient.USGovCloud},
+		{name: "china", cloud: "China", want: azureclient.ChinaCloud},
+		{
+			name:          "custom",
+			cloud:         "custom",
+			authorityHost: "https://login.example.com/",
+			graphEndpoint: "https://graph.example.com",
+			want:          azureclient.Cloud{AuthorityHost: "https://login.example.com", GraphEndpoint: "https://graph.example.com"},
+		},
+		{name: "custom without graph endpoint", cloud: "custom", authorityHost: "https://login.example.com", err: "no graph endpoint"},
+		{name: "custom with http authority", cloud: "custom", authorityHost: "http://login.example.com", graphEndpoint: "https://graph.example.com", err: "must be an https URL"},
+		{name: "unknown", cloud: "germany", err: "invalid cloud"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+			cloud, err := azureclient.LookupCloud(tt.cloud, tt.authorityHost, tt.graphEndpoint)
+			if tt.err != "" {
+				assert.Error(err)
+				assert.Contains(err.Error(), tt.err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tt.want, cloud)
+		})
+	}
+}
+
+func TestCloudEndpoints(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal("https://graph.microsoft.us/v1.0", azureclient.USGovCloud.BaseURL())
+	assert.Equal("https://microsoftgraph.chinacloudapi.cn/.default", azureclient.ChinaCloud.Scope())
+	assert.Equal("https://login.microsoftonline.us/tenant/oauth2/v2.0/token", azureclient.USGovCloud.TokenURL("tenant"))
+	assert.Equal(azureclient.PublicCloud.BaseURL(), azureclient.Cloud{}.BaseURL())
+}
+
+func TestNewClientInSovereignCloud(t *testing.T) {
+	credentials := map[string]azureclient.Credentials{
+		"secret": {
+			Kind:         azureclient.CredentialClientSecret,
+			Tenant:       "tenant",
+			ClientID:     "id",
+			ClientSecret: "secret",
+		},
+		"refresh token": {
+			Kind:         azureclient.CredentialRefreshToken,
+			Tenant:       "tenant",
+			ClientID:     "id",
+			ClientSecret: "secret",
+			RefreshToken: "refresh",
+		},
+	}
+
+	for name, c := range credentials {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			assert := require.New(t)
+
+			fake := newFakeAzure(t, "usgov-token")
+			var scope string
+			token := fake.token
+			fake.token = func(w http.ResponseWriter, r *http.Request) {
+				assert.NoError(r.ParseForm())
+				scope = r.PostForm.Get("scope")
+				token(w, r)
+			}
+
+			client, err := azureclient.NewClient(context.Background(), c,
+				&azureclient.Options{Cloud: azureclient.USGovCloud, HTTPClient: fake.client()})
+			assert.NoError(err)
+
+			_, err = client.ListUsers()
+			assert.NoError(err)
+
+			assert.Contains(fake.requests, "login.microsoftonline.us/tenant/oauth2/v2.0/token")
+			assert.Contains(fake.requests, "graph.microsoft.us/v1.0/users")
+			assert.Contains(scope, "https://graph.microsoft.us/.default")
+		})
+	}
+}
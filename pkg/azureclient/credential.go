@@ -2,11 +2,10 @@ package azureclient
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
@@ -15,11 +14,22 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// tokenExpirySkew is how long before its expiry a cached access token is refreshed.
+const tokenExpirySkew = 5 * time.Minute
+
+// RefreshTokenCredential redeems a delegated refresh token for access tokens. Access tokens are cached
+// until shortly before they expire, and the refresh tokens Azure AD rotates replace the current one.
 type RefreshTokenCredential struct {
 	clientID     string
 	clientSecret string
 	refreshToken string
 	tenantID     string
+	httpClient   *http.Client
+	cloud        Cloud
+	store        RefreshTokenStore
+
+	mu     sync.Mutex
+	tokens map[string]azcore.AccessToken
 }
 
 func NewRefreshTokenCredential(ctx context.Context, tenantID, clientID, clientSecret, refreshToken string) (*RefreshTokenCredential, error) {
@@ -28,50 +38,64 @@ func NewRefreshTokenCredential(ctx context.Context, tenantID, clientID, clientSe
 		clientSecret: clientSecret,
 		tenantID:     tenantID,
 		refreshToken: refreshToken,
+		httpClient:   http.DefaultClient,
+		cloud:        PublicCloud,
+		tokens:       make(map[string]azcore.AccessToken),
 	}
 	return c, nil
 }
 
+// GetToken returns a cached access token for the requested scopes, or redeems the refresh token for a new one.
+// Concurrent callers wait for a single token request.
 func (c *RefreshTokenCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
-	accessToken := azcore.AccessToken{}
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	url := "https://login.microsoftonline.com/" + c.tenantID + "/oauth2/v2.0/token"
-	data := fmt.Sprintf("grant_type=refresh_token&client_id=%s&client_secret=%s&refresh_token=%s",
-		c.clientID, c.clientSecret, c.refreshToken)
-	payload := strings.NewReader(data)
+	key := strings.Join(options.Scopes, " ")
+	if token, ok := c.tokens[key]; ok && time.Now().Add(tokenExpirySkew).Before(token.ExpiresOn) {
+		return token, nil
+	}
 
-	// create the request and execute it
-	req, err := http.NewRequestWithContext(ctx, "POST", url, payload)
+	token, refreshToken, err := c.redeem(ctx, options)
 	if err != nil {
-		return accessToken, err
+		return token, err
 	}
+	c.tokens[key] = token
 
-	req.Header.Add("content-type", "application/x-www-form-urlencoded")
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return accessToken, err
+	if refreshToken != "" && refreshToken != c.refreshToken {
+		c.refreshToken = refreshToken
+		if c.store != nil {
+			if err := c.store.Save(refreshToken); err != nil {
+				return token, status.Errorf(codes.Internal, "failed to save the rotated refresh token: %s", err.Error())
+			}
+		}
 	}
+	return token, nil
+}
 
-	// process the response
-	defer res.Body.Close()
-	var responseData map[string]interface{}
-	body, _ := io.ReadAll(res.Body)
+// redeem sends the refresh token to the token endpoint and returns the access token and the rotated refresh token.
+func (c *RefreshTokenCredential) redeem(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, string, error) {
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {c.clientID},
+		"client_secret": {c.clientSecret},
+		"refresh_token": {c.refreshToken},
+	}
+	if len(options.Scopes) > 0 {
+		form.Set("scope", strings.Join(options.Scopes, " "))
+	}
 
-	// unmarshal the json into a string map
-	err = json.Unmarshal(body, &responseData)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cloud.TokenURL(c.tenantID), strings.NewReader(form.Encode()))
 	if err != nil {
-		return accessToken, err
+		return azcore.AccessToken{}, "", status.Errorf(codes.Internal, "failed to create token request: %s", err.Error())
 	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	// check for error
-	if responseData["error_description"] != nil {
-		errorMessage := responseData["error_description"].(string)
-		return accessToken, status.Error(codes.InvalidArgument, errorMessage)
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return azcore.AccessToken{}, "", status.Errorf(codes.Unavailable, "token request failed: %s", err.Error())
 	}
+	defer res.Body.Close()
 
-	// retrieve the access token and expiration
-	accessToken.Token = responseData["access_token"].(string)
-	expiresIn := int(responseData["expires_in"].(float64))
-	accessToken.ExpiresOn = time.Now().Add(time.Second * time.Duration(expiresIn))
-	return accessToken, nil
+	return parseTokenResponse(res)
 }
@@ -0,0 +1,120 @@
+package azureclient_test
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/azureclient"
+	"github.com/stretchr/testify/require"
+)
+
+func refreshTokenCredentials(store azureclient.RefreshTokenStore) azureclient.Credentials {
+	return azureclient.Credentials{
+		Kind:              azureclient.CredentialRefreshToken,
+		Tenant:            "tenant",
+		ClientID:          "id",
+		ClientSecret:      "secret",
+		RefreshToken:      "original",
+		RefreshTokenStore: store,
+	}
+}
+
+func TestRefreshTokenCredentialCachesAccessToken(t *testing.T) {
+	assert := require.New(t)
+
+	fake := newFakeAzure(t, "access")
+	var requests int32
+	token := fake.token
+	fake.token = func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		token(w, r)
+	}
+
+	client, err := azureclient.NewClient(context.Background(), refreshTokenCredentials(nil), &azureclient.Options{HTTPClient: fake.client()})
+	assert.NoError(err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.ListUsers()
+			assert.NoError(err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestRefreshTokenCredentialRefreshesExpiringToken(t *testing.T) {
+	assert := require.New(t)
+
+	fake := newFakeAzure(t, "access")
+	var requests int32
+	fake.token = func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		// a token expiring within the skew is not reused
+		writeJSON(w, http.StatusOK, map[string]any{"access_token": "access", "token_type": "Bearer", "expires_in": 60})
+	}
+
+	client, err := azureclient.NewClient(context.Background(), refreshTokenCredentials(nil), &azureclient.Options{HTTPClient: fake.client()})
+	assert.NoError(err)
+
+	_, err = client.ListUsers()
+	assert.NoError(err)
+	_, err = client.ListUsers()
+	assert.NoError(err)
+
+	assert.Equal(int32(2), atomic.LoadInt32(&requests))
+}
+
+func TestRefreshTokenCredentialSavesRotatedToken(t *testing.T) {
+	assert := require.New(t)
+
+	store := &azureclient.FileRefreshTokenStore{Path: filepath.Join(t.TempDir(), "refresh-token.json")}
+
+	fake := newFakeAzure(t, "access")
+	var redeemed []string
+	fake.token = func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(r.ParseForm())
+		redeemed = append(redeemed, r.PostForm.Get("refresh_token"))
+		writeJSON(w, http.StatusOK, map[string]any{
+			"access_token":  "access",
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+			"refresh_token": "rotated",
+		})
+	}
+
+	client, err := azureclient.NewClient(context.Background(), refreshTokenCredentials(store), &azureclient.Options{HTTPClient: fake.client()})
+	assert.NoError(err)
+	_, err = client.ListUsers()
+	assert.NoError(err)
+
+	saved, err := store.Load()
+	assert.NoError(err)
+	assert.Equal("rotated", saved)
+
+	// the next client starts from the saved refresh token rather than the configured one
+	client, err = azureclient.NewClient(context.Background(), refreshTokenCredentials(store), &azureclient.Options{HTTPClient: fake.client()})
+	assert.NoError(err)
+	_, err = client.ListUsers()
+	assert.NoError(err)
+
+	assert.Equal([]string{"original", "rotated"}, redeemed)
+}
+
+func TestFileRefreshTokenStoreWithoutFile(t *testing.T) {
+	assert := require.New(t)
+
+	store := &azureclient.FileRefreshTokenStore{Path: filepath.Join(t.TempDir(), "missing.json")}
+	saved, err := store.Load()
+
+	assert.NoError(err)
+	assert.Empty(saved)
+}
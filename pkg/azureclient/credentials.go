@@ -0,0 +1,153 @@
+package azureclient
+
+import (
+	"cmp"
+	"context"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CredentialKind selects how the client authenticates to Azure AD.
+type CredentialKind string
+
+const (
+	// CredentialClientSecret authenticates the application with a client secret.
+	CredentialClientSecret CredentialKind = "client-secret"
+	// CredentialCertificate authenticates the application with a client certificate.
+	CredentialCertificate CredentialKind = "certificate"
+	// CredentialRefreshToken acts on behalf of a user with a delegated refresh token.
+	CredentialRefreshToken CredentialKind = "refresh-token"
+	// CredentialWorkloadIdentity authenticates the application with a federated token, such as
+	// a Kubernetes service account token projected into a file.
+	CredentialWorkloadIdentity CredentialKind = "workload-identity"
+	// CredentialManagedIdentity authenticates with the system-assigned managed identity of the host,
+	// or with a user-assigned one when a client id is set.
+	CredentialManagedIdentity CredentialKind = "managed-identity"
+)
+
+// The variables set by the Azure workload identity webhook in the pods it mutates.
+const (
+	envFederatedTokenFile = "AZURE_FEDERATED_TOKEN_FILE"
+	envTenantID           = "AZURE_TENANT_ID"
+	envClientID           = "AZURE_CLIENT_ID"
+)
+
+// Credentials holds the settings of every credential kind; only the ones used by Kind are read.
+type Credentials struct {
+	Kind                 CredentialKind
+	Tenant               string
+	ClientID             string
+	ClientSecret         string
+	RefreshToken         string
+	Certificate          string
+	CertificatePassword  string
+	SendCertificateChain bool
+	FederatedTokenFile   string
+	// RefreshTokenStore, when set, saves the rotated refresh tokens; a saved token takes precedence over RefreshToken.
+	RefreshTokenStore RefreshTokenStore
+}
+
+// NewClient creates a Graph client authenticating with the given credentials.
+func NewClient(ctx context.Context, credentials Credentials, options *Options) (*AzureADClient, error) {
+	policy := newRetryPolicy(options.retry())
+
+	credential, err := newCredential(ctx, credentials, options, policy)
+	if err != nil {
+		return nil, err
+	}
+	return newClient(credential, options, policy)
+}
+
+func newCredential(ctx context.Context, c Credentials, options *Options, policy *retryPolicy) (azcore.TokenCredential, error) {
+	clientOptions := options.clientOptions(policy)
+
+	switch c.Kind {
+	case CredentialClientSecret, "":
+		credential, err := azidentity.NewClientSecretCredential(c.Tenant, c.ClientID, c.ClientSecret,
+			&azidentity.ClientSecretCredentialOptions{ClientOptions: clientOptions})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create an Azure secret credential: %s", err.Error())
+		}
+		return credential, nil
+
+	case CredentialCertificate:
+		data, err := LoadCertificate(c.Certificate)
+		if err != nil {
+			return nil, err
+		}
+		certs, key, err := azidentity.ParseCertificates(data, []byte(c.CertificatePassword))
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to parse client certificate: %s", err.Error())
+		}
+		credential, err := azidentity.NewClientCertificateCredential(c.Tenant, c.ClientID, certs, key,
+			&azidentity.ClientCertificateCredentialOptions{ClientOptions: clientOptions, SendCertificateChain: c.SendCertificateChain})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create an Azure certificate credential: %s", err.Error())
+		}
+		return credential, nil
+
+	case CredentialRefreshToken:
+		refreshToken := c.RefreshToken
+		if c.RefreshTokenStore != nil {
+			saved, err := c.RefreshTokenStore.Load()
+			if err != nil {
+				return nil, err
+			}
+			refreshToken = cmp.Or(saved, refreshToken)
+		}
+		credential, err := NewRefreshTokenCredential(ctx, c.Tenant, c.ClientID, c.ClientSecret, refreshToken)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create Refresh Token credential: %s", err.Error())
+		}
+		credential.store = c.RefreshTokenStore
+		credential.httpClient = &http.Client{Transport: &RetryTransport{policy: policy, next: options.transport()}}
+		credential.cloud = options.cloud()
+		return credential, nil
+
+	case CredentialWorkloadIdentity:
+		tokenFile := cmp.Or(c.FederatedTokenFile, os.Getenv(envFederatedTokenFile))
+		if tokenFile == "" {
+			return nil, status.Error(codes.InvalidArgument, "no federated token file was provided")
+		}
+		credential, err := azidentity.NewClientAssertionCredential(
+			cmp.Or(c.Tenant, os.Getenv(envTenantID)),
+			cmp.Or(c.ClientID, os.Getenv(envClientID)),
+			federatedToken(tokenFile),
+			&azidentity.ClientAssertionCredentialOptions{ClientOptions: clientOptions})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create an Azure workload identity credential: %s", err.Error())
+		}
+		return credential, nil
+
+	case CredentialManagedIdentity:
+		miOptions := &azidentity.ManagedIdentityCredentialOptions{ClientOptions: clientOptions}
+		if c.ClientID != "" {
+			miOptions.ID = azidentity.ClientID(c.ClientID)
+		}
+		credential, err := azidentity.NewManagedIdentityCredential(miOptions)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create an Azure managed identity credential: %s", err.Error())
+		}
+		return credential, nil
+
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown credential kind %q", c.Kind)
+	}
+}
+
+// federatedToken reads the client assertion on every token request, since projected service account tokens rotate.
+func federatedToken(path string) func(context.Context) (string, error) {
+	return func(context.Context) (string, error) {
+		token, err := os.ReadFile(path)
+		if err != nil {
+			return "", status.Errorf(codes.Unavailable, "failed to read federated token file %s: %s", path, err.Error())
+		}
+		return strings.TrimSpace(string(token)), nil
+	}
+}
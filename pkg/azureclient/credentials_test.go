@@ -0,0 +1,206 @@
+package azureclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/azureclient"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAzure serves the Azure AD token endpoints and the Graph users endpoint on a local server.
+// Its client sends every request to that server, whatever the host of the request URL.
+type fakeAzure struct {
+	t      *testing.T
+	server *httptest.Server
+	// token handles the token requests: the OAuth2 token endpoint and the managed identity endpoint.
+	token func(w http.ResponseWriter, r *http.Request)
+	// accessToken is the bearer token Graph requests must carry.
+	accessToken string
+	// requests records the original host and path of every request.
+	requests []string
+	mu       sync.Mutex
+}
+
+func newFakeAzure(t *testing.T, accessToken string) *fakeAzure {
+	f := &fakeAzure{t: t, accessToken: accessToken}
+	f.token = func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{"access_token": accessToken, "token_type": "Bearer", "expires_in": 3600})
+	}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeAzure) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests = append(f.requests, r.Header.Get("X-Original-Host")+r.URL.Path)
+	f.mu.Unlock()
+	authority := "https://" + r.Header.Get("X-Original-Host")
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/discovery/instance"):
+		writeJSON(w, http.StatusOK, map[string]any{
+			"tenant_discovery_endpoint": authority + "/tenant/v2.0/.well-known/openid-configuration",
+			"api-version":               "1.1",
+			"metadata":                  []any{},
+		})
+	case strings.HasSuffix(r.URL.Path, "/.well-known/openid-configuration"):
+		tenant := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")[0]
+		writeJSON(w, http.StatusOK, map[string]any{
+			"token_endpoint":         authority + "/" + tenant + "/oauth2/v2.0/token",
+			"authorization_endpoint": authority + "/" + tenant + "/oauth2/v2.0/authorize",
+			"issuer":                 authority + "/" + tenant + "/v2.0",
+		})
+	case strings.HasSuffix(r.URL.Path, "/oauth2/v2.0/token"), strings.HasPrefix(r.URL.Path, "/msi/"):
+		f.token(w, r)
+	case strings.HasSuffix(r.URL.Path, "/users"):
+		if r.Header.Get("Authorization") != "Bearer "+f.accessToken {
+			writeJSON(w, http.StatusUnauthorized, map[string]any{"error": map[string]any{"code": "InvalidAuthenticationToken", "message": "bad token"}})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"value": []any{map[string]any{"id": "1", "displayName": "One"}}})
+	default:
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// client returns an HTTP client that redirects every request to the fake server.
+func (f *fakeAzure) client() *http.Client {
+	target, _ := url.Parse(f.server.URL)
+	return &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r = r.Clone(r.Context())
+		r.Header.Set("X-Original-Host", r.URL.Host)
+		r.URL.Scheme = target.Scheme
+		r.URL.Host = target.Host
+		r.Host = target.Host
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func writeJSON(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func TestNewClientWithWorkloadIdentity(t *testing.T) {
+	assert := require.New(t)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	assert.NoError(os.WriteFile(tokenFile, []byte("federated-jwt\n"), 0o600))
+
+	fake := newFakeAzure(t, "workload-token")
+	var assertion string
+	fake.token = func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(r.ParseForm())
+		assertion = r.PostForm.Get("client_assertion")
+		writeJSON(w, http.StatusOK, map[string]any{"access_token": "workload-token", "token_type": "Bearer", "expires_in": 3600})
+	}
+
+	client, err := azureclient.NewClient(context.Background(), azureclient.Credentials{
+		Kind:               azureclient.CredentialWorkloadIdentity,
+		Tenant:             "tenant",
+		ClientID:           "id",
+		FederatedTokenFile: tokenFile,
+	}, &azureclient.Options{HTTPClient: fake.client()})
+	assert.NoError(err)
+
+	users, err := client.ListUsers()
+	assert.NoError(err)
+	assert.Len(users.GetValue(), 1)
+	assert.Equal("federated-jwt", assertion)
+}
+
+func TestNewClientWithManagedIdentity(t *testing.T) {
+	assert := require.New(t)
+
+	t.Setenv("IDENTITY_ENDPOINT", "http://169.254.169.254/msi/token")
+	t.Setenv("IDENTITY_HEADER", "header")
+
+	fake := newFakeAzure(t, "managed-token")
+	var clientID string
+	fake.token = func(w http.ResponseWriter, r *http.Request) {
+		clientID = r.URL.Query().Get("client_id")
+		writeJSON(w, http.StatusOK, map[string]any{
+			"access_token": "managed-token",
+			"expires_on":   fmt.Sprint(time.Now().Add(time.Hour).Unix()),
+		})
+	}
+
+	client, err := azureclient.NewClient(context.Background(), azureclient.Credentials{
+		Kind:     azureclient.CredentialManagedIdentity,
+		ClientID: "user-assigned",
+	}, &azureclient.Options{HTTPClient: fake.client()})
+	assert.NoError(err)
+
+	users, err := client.ListUsers()
+	assert.NoError(err)
+	assert.Len(users.GetValue(), 1)
+	assert.Equal("user-assigned", clientID)
+}
+
+func TestNewClientRetriesThrottledTokenRequests(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxRetries int
+		requests   int
+		retries    int32
+	}{
+		{name: "retried and counted", maxRetries: 2, requests: 2, retries: 1},
+		{name: "retries disabled", maxRetries: -1, requests: 1, retries: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			fake := newFakeAzure(t, "secret-token")
+			requests := 0
+			fake.token = func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests == 1 {
+					w.Header().Set("Retry-After", "0")
+					writeJSON(w, http.StatusTooManyRequests, map[string]any{"error": "temporarily_unavailable"})
+					return
+				}
+				writeJSON(w, http.StatusOK, map[string]any{"access_token": "secret-token", "token_type": "Bearer", "expires_in": 3600})
+			}
+
+			client, err := azureclient.NewClient(context.Background(), azureclient.Credentials{
+				Kind:         azureclient.CredentialClientSecret,
+				Tenant:       "tenant",
+				ClientID:     "id",
+				ClientSecret: "secret",
+			}, &azureclient.Options{HTTPClient: fake.client(), Retry: azureclient.RetryOptions{MaxRetries: tt.maxRetries}})
+			assert.NoError(err)
+
+			_, err = client.ListUsers()
+			assert.Equal(tt.retries == 0, err != nil)
+			assert.Equal(tt.requests, requests)
+			assert.Equal(tt.retries, client.Retries())
+		})
+	}
+}
+
+func TestNewClientWithUnknownCredentialKind(t *testing.T) {
+	_, err := azureclient.NewClient(context.Background(), azureclient.Credentials{Kind: "password"}, nil)
+	require.Error(t, err)
+}
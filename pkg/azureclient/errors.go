@@ -0,0 +1,65 @@
+package azureclient
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/models/odataerrors"
+	abs "github.com/microsoft/kiota-abstractions-go"
+)
+
+// deltaExpiredCodes are the Graph error codes of a delta link that can no longer be resumed.
+var deltaExpiredCodes = map[string]bool{
+	"syncStateNotFound": true,
+	"syncStateInvalid":  true,
+	"resyncRequired":    true,
+}
+
+// notFoundCodes are the Graph error codes of a request for an object that does not exist.
+var notFoundCodes = map[string]bool{
+	"Request_ResourceNotFound": true,
+	"ResourceNotFound":         true,
+}
+
+// GraphErrorCode returns the OData error code of a Graph error response, or an empty string if err is not one.
+func GraphErrorCode(err error) string {
+	var odataErr *odataerrors.ODataError
+	if !errors.As(err, &odataErr) || odataErr.GetError() == nil || odataErr.GetError().GetCode() == nil {
+		return ""
+	}
+	return *odataErr.GetError().GetCode()
+}
+
+// GraphErrorMessage returns the message of a Graph error response, falling back to err.Error().
+func GraphErrorMessage(err error) string {
+	var odataErr *odataerrors.ODataError
+	if !errors.As(err, &odataErr) || odataErr.GetError() == nil || odataErr.GetError().GetMessage() == nil {
+		return err.Error()
+	}
+	if code := GraphErrorCode(err); code != "" {
+		return code + ": " + *odataErr.GetError().GetMessage()
+	}
+	return *odataErr.GetError().GetMessage()
+}
+
+// IsDeltaExpired reports whether Graph rejected a saved delta link, because it expired or its state was lost.
+// A new delta round has to be started from scratch.
+func IsDeltaExpired(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr *abs.ApiError
+	if errors.As(err, &apiErr) && apiErr.ResponseStatusCode == http.StatusGone {
+		return true
+	}
+	return deltaExpiredCodes[GraphErrorCode(err)]
+}
+
+// isNotFound reports whether Graph rejected a request because the object it targets does not exist.
+func isNotFound(err error) bool {
+	var apiErr *abs.ApiError
+	if errors.As(err, &apiErr) && apiErr.ResponseStatusCode == http.StatusNotFound {
+		return true
+	}
+	return notFoundCodes[GraphErrorCode(err)]
+}
@@ -0,0 +1,107 @@
+package azureclient
+
+import (
+	"context"
+	"slices"
+
+	adapplications "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/applications"
+	appitem "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/applications/item"
+	appextensionproperties "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/applications/item/extensionproperties"
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/models"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	applicationFields       = []string{"id", "appId", "displayName"}
+	extensionPropertyFields = []string{"id", "name", "dataType", "isMultiValued", "targetObjects"}
+)
+
+// extensionTargetUser is the target object of the directory extension properties defined on users.
+const extensionTargetUser = "User"
+
+// ResolveApplication returns the application registration whose object id or, failing that, app id is ref.
+func (c *AzureADClient) ResolveApplication(ref string) (models.Applicationable, error) {
+	ctx := context.Background()
+
+	if _, err := uuid.Parse(ref); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "application %q is neither an object id nor an app id", ref)
+	}
+
+	app, err := c.appClient.ApplicationsById(ref).Get(ctx,
+		&appitem.ApplicationItemRequestBuilderGetRequestConfiguration{
+			QueryParameters: &appitem.ApplicationItemRequestBuilderGetQueryParameters{
+				Select: applicationFields,
+			},
+		})
+	if err == nil {
+		return app, nil
+	}
+	if GraphErrorCode(err) != "Request_ResourceNotFound" {
+		return nil, status.Errorf(codes.Internal, "failed to get application %s: %s", ref, GraphErrorMessage(err))
+	}
+
+	filter, err := Eq("appId", ref).Build()
+	if err != nil {
+		return nil, err
+	}
+	page, err := c.appClient.Applications().Get(ctx,
+		&adapplications.ApplicationsRequestBuilderGetRequestConfiguration{
+			QueryParameters: &adapplications.ApplicationsRequestBuilderGetQueryParameters{
+				Filter: &filter,
+				Select: applicationFields,
+			},
+		})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to look up application %s: %s", ref, GraphErrorMessage(err))
+	}
+	if len(page.GetValue()) == 0 {
+		return nil, status.Errorf(codes.NotFound, "application %s was not found", ref)
+	}
+	return page.GetValue()[0], nil
+}
+
+// ListUserExtensionProperties returns the directory extension properties registered on the given application
+// that apply to users. Their names, such as extension_{appId}_{name}, are the Graph user properties holding them.
+func (c *AzureADClient) ListUserExtensionProperties(appRef string) ([]models.ExtensionPropertyable, error) {
+	ctx := context.Background()
+
+	app, err := c.ResolveApplication(appRef)
+	if err != nil {
+		return nil, err
+	}
+	if app.GetId() == nil {
+		return nil, status.Errorf(codes.Internal, "application %s has no object id", appRef)
+	}
+	appID := *app.GetId()
+
+	page, err := c.appClient.ApplicationsById(appID).ExtensionProperties().Get(ctx,
+		&appextensionproperties.ExtensionPropertiesRequestBuilderGetRequestConfiguration{
+			QueryParameters: &appextensionproperties.ExtensionPropertiesRequestBuilderGetQueryParameters{
+				Select: extensionPropertyFields,
+			},
+		})
+
+	var properties []models.ExtensionPropertyable
+	for {
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to list extension properties of application %s: %s", appRef, GraphErrorMessage(err))
+		}
+		if page == nil {
+			return properties, nil
+		}
+
+		for _, property := range page.GetValue() {
+			if property.GetName() != nil && slices.Contains(property.GetTargetObjects(), extensionTargetUser) {
+				properties = append(properties, property)
+			}
+		}
+
+		next := page.GetOdataNextLink()
+		if next == nil || *next == "" {
+			return properties, nil
+		}
+		page, err = appextensionproperties.NewExtensionPropertiesRequestBuilder(*next, c.adapter).Get(ctx, nil)
+	}
+}
@@ -0,0 +1,26 @@
+package azureclient
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic replaces the file at path with data, writing a temporary file next to it and renaming it
+// over path, so that readers never see a partial file. The file is readable by its owner only.
+func WriteFileAtomic(path string, data []byte) error {
+	// CreateTemp creates the file with 0600 permissions
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
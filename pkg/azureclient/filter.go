@@ -0,0 +1,191 @@
+package azureclient
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// propertyPattern matches property paths such as mail, onPremisesExtensionAttributes/extensionAttribute1
+	// or the i/issuer member of a lambda variable.
+	propertyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(/[A-Za-z_][A-Za-z0-9_]*)*$`)
+	variablePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Filter is an OData $filter expression. Values are always written as escaped literals, so that user input
+// cannot change the meaning of the expression. The zero Filter matches everything and is skipped by And and Or.
+type Filter struct {
+	expr string
+	// composite is set for and/or expressions, which are parenthesized when nested.
+	composite bool
+	err       error
+}
+
+// Eq matches the items whose property equals value.
+func Eq(property string, value any) Filter {
+	return compare(property, "eq", value)
+}
+
+// Ne matches the items whose property differs from value.
+func Ne(property string, value any) Filter {
+	return compare(property, "ne", value)
+}
+
+// StartsWith matches the items whose string property starts with prefix.
+func StartsWith(property, prefix string) Filter {
+	if err := validateProperty(property); err != nil {
+		return Filter{err: err}
+	}
+	return Filter{expr: fmt.Sprintf("startswith(%s,%s)", property, Literal(prefix))}
+}
+
+// In matches the items whose property equals one of values.
+func In(property string, values ...any) Filter {
+	if err := validateProperty(property); err != nil {
+		return Filter{err: err}
+	}
+	if len(values) == 0 {
+		return Filter{err: status.Errorf(codes.InvalidArgument, "no values were provided for %s in", property)}
+	}
+
+	literals := make([]string, 0, len(values))
+	for _, value := range values {
+		literal, err := literalOf(value)
+		if err != nil {
+			return Filter{err: err}
+		}
+		literals = append(literals, literal)
+	}
+	return Filter{expr: fmt.Sprintf("%s in (%s)", property, strings.Join(literals, ","))}
+}
+
+// Any matches the items with at least one element of collection satisfying condition, in which the
+// element is named variable; for example Any("identities", "i", Eq("i/issuer", "contoso.com")).
+func Any(collection, variable string, condition Filter) Filter {
+	if err := validateProperty(collection); err != nil {
+		return Filter{err: err}
+	}
+	if !variablePattern.MatchString(variable) {
+		return Filter{err: status.Errorf(codes.InvalidArgument, "invalid filter variable %q", variable)}
+	}
+	if condition.err != nil {
+		return condition
+	}
+	if condition.expr == "" {
+		return Filter{expr: fmt.Sprintf("%s/any()", collection)}
+	}
+	return Filter{expr: fmt.Sprintf("%s/any(%s:%s)", collection, variable, condition.expr)}
+}
+
+// Raw wraps an expression written by hand, such as a configured filter. It is sent as is, and parenthesized
+// when composed with other filters.
+func Raw(expr string) Filter {
+	expr = strings.TrimSpace(expr)
+	return Filter{expr: expr, composite: expr != ""}
+}
+
+// And matches the items satisfying all of filters.
+func And(filters ...Filter) Filter {
+	return join("and", filters)
+}
+
+// Or matches the items satisfying any of filters.
+func Or(filters ...Filter) Filter {
+	return join("or", filters)
+}
+
+// Build returns the $filter expression, or the first error met while composing it.
+func (f Filter) Build() (string, error) {
+	return f.expr, f.err
+}
+
+// IsZero reports whether the filter is empty.
+func (f Filter) IsZero() bool {
+	return f.expr == "" && f.err == nil
+}
+
+// String returns the expression, for logging.
+func (f Filter) String() string {
+	return f.expr
+}
+
+// Literal returns value as an OData string literal, doubling its single quotes.
+func Literal(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func compare(property, operator string, value any) Filter {
+	if err := validateProperty(property); err != nil {
+		return Filter{err: err}
+	}
+	literal, err := literalOf(value)
+	if err != nil {
+		return Filter{err: err}
+	}
+	return Filter{expr: fmt.Sprintf("%s %s %s", property, operator, literal)}
+}
+
+func join(operator string, filters []Filter) Filter {
+	var kept []Filter
+	for _, f := range filters {
+		if f.err != nil {
+			return f
+		}
+		if f.expr != "" {
+			kept = append(kept, f)
+		}
+	}
+
+	switch len(kept) {
+	case 0:
+		return Filter{}
+	case 1:
+		return kept[0]
+	}
+
+	operands := make([]string, 0, len(kept))
+	for _, f := range kept {
+		if f.composite {
+			operands = append(operands, "("+f.expr+")")
+		} else {
+			operands = append(operands, f.expr)
+		}
+	}
+	return Filter{expr: strings.Join(operands, " "+operator+" "), composite: true}
+}
+
+// literalOf formats a value as an OData literal. Directory object ids are compared as strings.
+func literalOf(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return Literal(v), nil
+	case uuid.UUID:
+		return Literal(v.String()), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case time.Time:
+		return v.UTC().Format(time.RFC3339), nil
+	default:
+		return "", status.Errorf(codes.InvalidArgument, "unsupported filter value type %T", value)
+	}
+}
+
+func validateProperty(property string) error {
+	if !propertyPattern.MatchString(property) {
+		return status.Errorf(codes.InvalidArgument, "invalid filter property %q", property)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package azureclient_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/azureclient"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter azureclient.Filter
+		want   string
+		err    string
+	}{
+		{name: "eq", filter: azureclient.Eq("mail", "o'brien@test.com"), want: "mail eq 'o''brien@test.com'"},
+		{name: "ne bool", filter: azureclient.Ne("accountEnabled", false), want: "accountEnabled ne false"},
+		{name: "eq uuid", filter: azureclient.Eq("id", uuid.MustParse("6a9d4d24-0b89-4f7b-9c3e-7b1f5e1f2d3a")), want: "id eq '6a9d4d24-0b89-4f7b-9c3e-7b1f5e1f2d3a'"},
+		{name: "eq time", filter: azureclient.Eq("createdDateTime", time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)), want: "createdDateTime eq 2023-01-02T03:04:05Z"},
+		{name: "nested property", filter: azureclient.Eq("onPremisesExtensionAttributes/extensionAttribute1", "x"), want: "onPremisesExtensionAttributes/extensionAttribute1 eq 'x'"},
+		{name: "startswith", filter: azureclient.StartsWith("displayName", "Al'"), want: "startswith(displayName,'Al''')"},
+		{name: "in", filter: azureclient.In("userType", "Member", "Guest"), want: "userType in ('Member','Guest')"},
+		{
+			name:   "any",
+			filter: azureclient.Any("identities", "i", azureclient.And(azureclient.Eq("i/issuer", "contoso.com"), azureclient.Eq("i/issuerAssignedId", "bob"))),
+			want:   "identities/any(i:i/issuer eq 'contoso.com' and i/issuerAssignedId eq 'bob')",
+		},
+		{
+			name: "nested composition",
+			filter: azureclient.And(
+				azureclient.Eq("accountEnabled", true),
+				azureclient.Or(azureclient.Eq("userType", "Member"), azureclient.StartsWith("mail", "a")),
+			),
+			want: "accountEnabled eq true and (userType eq 'Member' or startswith(mail,'a'))",
+		},
+		{name: "empty operands are skipped", filter: azureclient.And(azureclient.Filter{}, azureclient.Eq("id", "1"), azureclient.Or()), want: "id eq '1'"},
+		{name: "empty", filter: azureclient.And(), want: ""},
+		{name: "raw", filter: azureclient.And(azureclient.Raw(" userType eq 'Member' or userType eq 'Guest' "), azureclient.Eq("accountEnabled", true)), want: "(userType eq 'Member' or userType eq 'Guest') and accountEnabled eq true"},
+		{name: "invalid property", filter: azureclient.Eq("mail eq 'x' or id", "y"), err: "invalid filter property"},
+		{name: "invalid variable", filter: azureclient.Any("identities", "i:x", azureclient.Eq("i/issuer", "y")), err: "invalid filter variable"},
+		{name: "empty in", filter: azureclient.In("id"), err: "no values"},
+		{name: "unsupported value", filter: azureclient.Eq("id", 1.5), err: "unsupported filter value type"},
+		{name: "error propagates", filter: azureclient.Or(azureclient.Eq("id", "1"), azureclient.Eq("", "2")), err: "invalid filter property"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+			got, err := tt.filter.Build()
+			if tt.err != "" {
+				assert.Error(err)
+				assert.Contains(err.Error(), tt.err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestGetUserByEmailEscapesQuotes(t *testing.T) {
+	assert := require.New(t)
+
+	var filters []string
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filters = append(filters, r.URL.Query().Get("$filter"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"value":[{"id":"42"}]}`)
+	}))
+
+	_, err := client.GetUserByEmail("x' or mail eq 'admin@test.com")
+	assert.NoError(err)
+	assert.Equal([]string{"mail eq 'x'' or mail eq ''admin@test.com'"}, filters)
+}
+
+// scanLiteral reads the OData string literal at the start of s and returns its value and the rest of s.
+func scanLiteral(s string) (string, string, bool) {
+	if !strings.HasPrefix(s, "'") {
+		return "", s, false
+	}
+	var value strings.Builder
+	for i := 1; i < len(s); i++ {
+		if s[i] != '\'' {
+			value.WriteByte(s[i])
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '\'' {
+			value.WriteByte('\'')
+			i++
+			continue
+		}
+		return value.String(), s[i+1:], true
+	}
+	return "", "", false
+}
+
+func FuzzLiteral(f *testing.F) {
+	for _, seed := range []string{"", "'", "''", "a'b", "' or 1 eq 1 or '", "o'brien@test.com", "\x00'\n"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, value string) {
+		// the literal must end exactly where it was written and read back as the original value
+		got, rest, ok := scanLiteral(azureclient.Literal(value))
+		if !ok || rest != "" || got != value {
+			t.Fatalf("literal of %q escapes: value %q, rest %q", value, got, rest)
+		}
+	})
+}
+
+func FuzzEq(f *testing.F) {
+	f.Add("name@test.com", "id")
+	f.Add("x' or mail eq 'admin@test.com", "o'brien")
+	f.Fuzz(func(t *testing.T, a, b string) {
+		expr, err := azureclient.Or(azureclient.Eq("mail", a), azureclient.StartsWith("userPrincipalName", b)).Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rest := strings.TrimPrefix(expr, "mail eq ")
+		got, rest, ok := scanLiteral(rest)
+		if !ok || got != a {
+			t.Fatalf("first literal escapes in %q", expr)
+		}
+		rest = strings.TrimPrefix(rest, " or startswith(userPrincipalName,")
+		got, rest, ok = scanLiteral(rest)
+		if !ok || got != b || rest != ")" {
+			t.Fatalf("second literal escapes in %q", expr)
+		}
+	})
+}
@@ -0,0 +1,223 @@
+package azureclient
+
+import (
+	"context"
+
+	adgroups "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/groups"
+	adgroupitem "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/groups/item"
+	admemberusers "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/groups/item/transitivemembers/user"
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/models"
+	admemberof "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/users/item/memberof"
+	adtransitivememberof "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/users/item/transitivememberof"
+	"github.com/google/uuid"
+	abs "github.com/microsoft/kiota-abstractions-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var groupFields = []string{"id", "displayName", "mailNickname"}
+
+// ListUserGroups returns the groups a user is a direct member of or, when transitive is set,
+// a member of through nested groups. Directory roles and administrative units are skipped.
+func (c *AzureADClient) ListUserGroups(userID string, transitive bool) ([]models.Groupable, error) {
+	ctx := context.Background()
+
+	requestInfo, err := c.userGroupsRequest(ctx, userID, transitive)
+	if err != nil {
+		return nil, err
+	}
+	page, err := c.adapter.Send(ctx, requestInfo, models.CreateDirectoryObjectCollectionResponseFromDiscriminatorValue, graphErrorMapping)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list groups of user %s: %s", userID, GraphErrorMessage(err))
+	}
+	groups, _ := page.(models.DirectoryObjectCollectionResponseable)
+	return c.collectGroups(ctx, userID, groups)
+}
+
+// ListUsersGroups returns, by user id, the groups ListUserGroups returns for each of the given users.
+// The first page of every user is requested through $batch.
+func (c *AzureADClient) ListUsersGroups(userIDs []string, transitive bool) (map[string][]models.Groupable, error) {
+	ctx := context.Background()
+
+	requests := make([]*abs.RequestInformation, 0, len(userIDs))
+	for _, userID := range userIDs {
+		requestInfo, err := c.userGroupsRequest(ctx, userID, transitive)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, requestInfo)
+	}
+	pages, err := c.batchGet(ctx, requests, models.CreateDirectoryObjectCollectionResponseFromDiscriminatorValue)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string][]models.Groupable, len(userIDs))
+	for i, userID := range userIDs {
+		page, ok := pages[i].(models.DirectoryObjectCollectionResponseable)
+		if ok {
+			groups[userID], err = c.collectGroups(ctx, userID, page)
+		} else {
+			groups[userID], err = c.ListUserGroups(userID, transitive)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return groups, nil
+}
+
+func (c *AzureADClient) userGroupsRequest(ctx context.Context, userID string, transitive bool) (*abs.RequestInformation, error) {
+	var requestInfo *abs.RequestInformation
+	var err error
+	if transitive {
+		requestInfo, err = c.appClient.UsersById(userID).TransitiveMemberOf().ToGetRequestInformation(ctx,
+			&adtransitivememberof.TransitiveMemberOfRequestBuilderGetRequestConfiguration{
+				QueryParameters: &adtransitivememberof.TransitiveMemberOfRequestBuilderGetQueryParameters{
+					Select: groupFields,
+				},
+			})
+	} else {
+		requestInfo, err = c.appClient.UsersById(userID).MemberOf().ToGetRequestInformation(ctx,
+			&admemberof.MemberOfRequestBuilderGetRequestConfiguration{
+				QueryParameters: &admemberof.MemberOfRequestBuilderGetQueryParameters{
+					Select: groupFields,
+				},
+			})
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to build groups request of user %s: %s", userID, err.Error())
+	}
+	return requestInfo, nil
+}
+
+// collectGroups keeps the groups of a memberOf page, then of the pages following it.
+func (c *AzureADClient) collectGroups(ctx context.Context, userID string, page models.DirectoryObjectCollectionResponseable) ([]models.Groupable, error) {
+	var groups []models.Groupable
+	var err error
+	for {
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to list groups of user %s: %s", userID, GraphErrorMessage(err))
+		}
+		if page == nil {
+			return groups, nil
+		}
+
+		for _, obj := range page.GetValue() {
+			if group, ok := obj.(models.Groupable); ok {
+				groups = append(groups, group)
+			}
+		}
+
+		next := page.GetOdataNextLink()
+		if next == nil || *next == "" {
+			return groups, nil
+		}
+		// transitiveMemberOf and memberOf pages share the same shape, either builder can follow the link
+		page, err = admemberof.NewMemberOfRequestBuilder(*next, c.adapter).Get(ctx, nil)
+	}
+}
+
+// ResolveGroup returns the group whose object id or, failing that, display name is ref.
+// A display name shared by several groups is rejected, as the group to read would be ambiguous.
+func (c *AzureADClient) ResolveGroup(ref string) (models.Groupable, error) {
+	ctx := context.Background()
+
+	if _, err := uuid.Parse(ref); err == nil {
+		group, err := c.appClient.GroupsById(ref).Get(ctx, &adgroupitem.GroupItemRequestBuilderGetRequestConfiguration{
+			QueryParameters: &adgroupitem.GroupItemRequestBuilderGetQueryParameters{
+				Select: groupFields,
+			},
+		})
+		if err == nil {
+			return group, nil
+		}
+		if GraphErrorCode(err) != "Request_ResourceNotFound" {
+			return nil, status.Errorf(codes.Internal, "failed to get group %s: %s", ref, GraphErrorMessage(err))
+		}
+	}
+
+	filter, err := Eq("displayName", ref).Build()
+	if err != nil {
+		return nil, err
+	}
+	page, err := c.appClient.Groups().Get(ctx, &adgroups.GroupsRequestBuilderGetRequestConfiguration{
+		QueryParameters: &adgroups.GroupsRequestBuilderGetQueryParameters{
+			Filter: &filter,
+			Select: groupFields,
+		},
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to look up group %s: %s", ref, GraphErrorMessage(err))
+	}
+
+	switch groups := page.GetValue(); len(groups) {
+	case 0:
+		return nil, status.Errorf(codes.NotFound, "group %s was not found", ref)
+	case 1:
+		return groups[0], nil
+	default:
+		return nil, status.Errorf(codes.FailedPrecondition, "%d groups are named %s; use the group id instead", len(groups), ref)
+	}
+}
+
+// listGroupMemberIDs returns the ids of the users that are members of a group, directly or through nested groups.
+func (c *AzureADClient) listGroupMemberIDs(ctx context.Context, groupID string) ([]string, error) {
+	page, err := c.appClient.GroupsById(groupID).TransitiveMembers().User().Get(ctx,
+		&admemberusers.UserRequestBuilderGetRequestConfiguration{
+			QueryParameters: &admemberusers.UserRequestBuilderGetQueryParameters{
+				Select: []string{"id"},
+			},
+		})
+
+	var ids []string
+	for {
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to list members of group %s: %s", groupID, GraphErrorMessage(err))
+		}
+		if page == nil {
+			return ids, nil
+		}
+
+		for _, user := range page.GetValue() {
+			if user.GetId() != nil {
+				ids = append(ids, *user.GetId())
+			}
+		}
+
+		next := page.GetOdataNextLink()
+		if next == nil || *next == "" {
+			return ids, nil
+		}
+		page, err = admemberusers.NewUserRequestBuilder(*next, c.adapter).Get(ctx, nil)
+	}
+}
+
+// ListGroupMembersPaged returns an iterator over the users that are members of the given groups, directly
+// or through nested groups. Users that are members of several groups are returned once.
+func (c *AzureADClient) ListGroupMembersPaged(groupIDs []string) *UserPageIterator {
+	index := 0
+	return &UserPageIterator{
+		first: func(ctx context.Context) (userPage, error) {
+			if index >= len(groupIDs) {
+				return nil, nil
+			}
+			groupID := groupIDs[index]
+			index++
+			return c.appClient.GroupsById(groupID).TransitiveMembers().User().Get(ctx,
+				&admemberusers.UserRequestBuilderGetRequestConfiguration{
+					QueryParameters: &admemberusers.UserRequestBuilderGetQueryParameters{
+						Select: c.fields,
+						Expand: c.expand,
+					},
+				})
+		},
+		next: func(ctx context.Context, link string) (userPage, error) {
+			return admemberusers.NewUserRequestBuilder(link, c.adapter).Get(ctx, nil)
+		},
+		more: func() bool {
+			return index < len(groupIDs)
+		},
+		seen: make(map[string]bool),
+	}
+}
@@ -0,0 +1,93 @@
+package azureclient
+
+import (
+	"context"
+	"io"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/models"
+)
+
+// userPage is the part of a Graph user collection response the iterator relies on.
+type userPage interface {
+	GetValue() []models.Userable
+	GetOdataNextLink() *string
+}
+
+// deltaPage is implemented by delta query responses, whose last page carries an @odata.deltaLink.
+type deltaPage interface {
+	GetOdataDeltaLink() *string
+}
+
+// UserPageIterator walks a paged user collection by following the @odata.nextLink of each response.
+type UserPageIterator struct {
+	first     func(ctx context.Context) (userPage, error)
+	next      func(ctx context.Context, link string) (userPage, error)
+	nextLink  string
+	deltaLink string
+	started   bool
+	// more, when set, reports whether another collection follows the current one; first is called again to start it.
+	more func() bool
+	// seen, when set, drops the users already returned by a previous page.
+	seen map[string]bool
+}
+
+// HasNext reports whether another page can be fetched.
+func (it *UserPageIterator) HasNext() bool {
+	return !it.started || it.nextLink != "" || (it.more != nil && it.more())
+}
+
+// DeltaLink returns the @odata.deltaLink of a delta query once its last page has been read.
+func (it *UserPageIterator) DeltaLink() string {
+	return it.deltaLink
+}
+
+// Next fetches the next page of users, returning io.EOF once the collection is exhausted.
+func (it *UserPageIterator) Next(ctx context.Context) ([]models.Userable, error) {
+	if !it.HasNext() {
+		return nil, io.EOF
+	}
+
+	var page userPage
+	var err error
+	if !it.started || it.nextLink == "" {
+		page, err = it.first(ctx)
+	} else {
+		page, err = it.next(ctx, it.nextLink)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	it.started = true
+	it.nextLink = ""
+	if page == nil {
+		return nil, nil
+	}
+	if next := page.GetOdataNextLink(); next != nil {
+		it.nextLink = *next
+	}
+	if delta, ok := page.(deltaPage); ok && delta.GetOdataDeltaLink() != nil {
+		it.deltaLink = *delta.GetOdataDeltaLink()
+	}
+	if it.seen == nil {
+		return page.GetValue(), nil
+	}
+
+	users := make([]models.Userable, 0, len(page.GetValue()))
+	for _, user := range page.GetValue() {
+		if id := user.GetId(); id != nil {
+			if it.seen[*id] {
+				continue
+			}
+			it.seen[*id] = true
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// IsRemoved reports whether a user returned by a delta query was removed from the directory.
+func IsRemoved(user models.Userable) bool {
+	_, ok := user.GetAdditionalData()["@removed"]
+	return ok
+}
@@ -0,0 +1,56 @@
+package azureclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	abs "github.com/microsoft/kiota-abstractions-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PhotoSizes lists the sizes Graph stores profile photos in.
+var PhotoSizes = []string{"48x48", "64x64", "96x96", "120x120", "240x240", "360x360", "432x432", "504x504", "648x648"}
+
+// photoNotFoundCodes are the error codes of the users without a photo, or without the mailbox photos are kept in.
+var photoNotFoundCodes = map[string]bool{
+	"ImageNotFound":               true,
+	"ErrorItemNotFound":           true,
+	"ResourceNotFound":            true,
+	"Request_ResourceNotFound":    true,
+	"MailboxNotEnabledForRESTAPI": true,
+}
+
+// Photo is the content of a profile photo.
+type Photo struct {
+	Content     []byte
+	ContentType string
+}
+
+// GetUserPhoto downloads the profile photo of a user in one of PhotoSizes. It returns nil when the user has no photo.
+func (c *AzureADClient) GetUserPhoto(ctx context.Context, userID, size string) (*Photo, error) {
+	content, err := c.appClient.UsersById(userID).PhotosById(size).Content().Get(ctx, nil)
+	if isPhotoNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get photo of user %s: %s", userID, GraphErrorMessage(err))
+	}
+	if len(content) == 0 {
+		return nil, nil
+	}
+	// the adapter does not expose the response headers, and Graph serves JPEG photos in practice
+	return &Photo{Content: content, ContentType: http.DetectContentType(content)}, nil
+}
+
+func isPhotoNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr *abs.ApiError
+	if errors.As(err, &apiErr) && apiErr.ResponseStatusCode == http.StatusNotFound {
+		return true
+	}
+	return photoNotFoundCodes[GraphErrorCode(err)]
+}
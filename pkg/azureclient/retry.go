@@ -0,0 +1,202 @@
+package azureclient
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	kiotahttp "github.com/microsoft/kiota-http-go"
+)
+
+const (
+	defaultMaxRetries = 5
+	defaultBaseDelay  = time.Second
+	defaultMaxDelay   = time.Minute
+)
+
+// RetryOptions bounds the retries of throttled (429) and unavailable (503, 504) Graph and token requests.
+type RetryOptions struct {
+	// MaxRetries is the number of retries of a single request; zero uses the default and a negative value disables retries.
+	MaxRetries int
+	// BaseDelay is the first backoff delay, doubled on every retry; zero uses the default.
+	BaseDelay time.Duration
+	// MaxDelay caps a single wait, including the ones requested through Retry-After; zero uses the default.
+	MaxDelay time.Duration
+}
+
+func (o RetryOptions) withDefaults() RetryOptions {
+	if o.MaxRetries == 0 {
+		o.MaxRetries = defaultMaxRetries
+	}
+	if o.MaxRetries < 0 {
+		o.MaxRetries = 0
+	}
+	if o.BaseDelay <= 0 {
+		o.BaseDelay = defaultBaseDelay
+	}
+	if o.MaxDelay <= 0 {
+		o.MaxDelay = defaultMaxDelay
+	}
+	return o
+}
+
+// retryPolicy retries requests that fail with a retriable status, honoring Retry-After and otherwise
+// backing off exponentially with full jitter. It counts the retries it makes.
+type retryPolicy struct {
+	options RetryOptions
+	retries int32
+}
+
+func newRetryPolicy(options RetryOptions) *retryPolicy {
+	return &retryPolicy{options: options.withDefaults()}
+}
+
+func (p *retryPolicy) count() int32 {
+	return atomic.LoadInt32(&p.retries)
+}
+
+func (p *retryPolicy) do(req *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	if err := rewindable(req); err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := send(req)
+		if err != nil || !retriable(resp.StatusCode) || attempt >= p.options.MaxRetries {
+			return resp, err
+		}
+
+		delay := p.delay(resp, attempt)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if err := sleepContext(req, delay); err != nil {
+			return nil, err
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		atomic.AddInt32(&p.retries, 1)
+	}
+}
+
+func (p *retryPolicy) delay(resp *http.Response, attempt int) time.Duration {
+	if delay, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+		if delay > p.options.MaxDelay {
+			return p.options.MaxDelay
+		}
+		return delay
+	}
+
+	backoff := p.options.BaseDelay << attempt
+	if backoff <= 0 || backoff > p.options.MaxDelay {
+		backoff = p.options.MaxDelay
+	}
+	// nolint:gosec // jitter does not need a cryptographic source
+	return time.Duration(rand.Int63n(int64(backoff) + 1))
+}
+
+func retriable(code int) bool {
+	return code == http.StatusTooManyRequests || code == http.StatusServiceUnavailable || code == http.StatusGatewayTimeout
+}
+
+// retryAfter parses a Retry-After header given either in seconds or as an HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+// rewindable buffers the request body so that it can be sent again.
+func rewindable(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return nil
+	}
+	content, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return err
+	}
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(content)), nil
+	}
+	req.Body, _ = req.GetBody()
+	return nil
+}
+
+func sleepContext(req *http.Request, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-req.Context().Done():
+		return req.Context().Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryMiddleware plugs the retry policy into the Graph request adapter pipeline.
+type retryMiddleware struct {
+	policy *retryPolicy
+}
+
+func (m *retryMiddleware) Intercept(pipeline kiotahttp.Pipeline, middlewareIndex int, req *http.Request) (*http.Response, error) {
+	return m.policy.do(req, func(r *http.Request) (*http.Response, error) {
+		return pipeline.Next(r, middlewareIndex)
+	})
+}
+
+// RetryTransport is an http.RoundTripper that retries throttled and unavailable responses,
+// for requests sent outside of the Graph request adapter.
+type RetryTransport struct {
+	policy *retryPolicy
+	next   http.RoundTripper
+}
+
+// NewRetryTransport wraps next, or http.DefaultTransport when nil, with the given retry options.
+func NewRetryTransport(next http.RoundTripper, options RetryOptions) *RetryTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &RetryTransport{policy: newRetryPolicy(options), next: next}
+}
+
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.policy.do(req, t.next.RoundTrip)
+}
+
+// Retries returns the number of requests retried by the transport.
+func (t *RetryTransport) Retries() int32 {
+	return t.policy.count()
+}
+
+// graphMiddlewares returns the default kiota middlewares with the retry handler replaced by the retry policy.
+func graphMiddlewares(policy *retryPolicy) []kiotahttp.Middleware {
+	middlewares := []kiotahttp.Middleware{&retryMiddleware{policy: policy}}
+	for _, middleware := range kiotahttp.GetDefaultMiddlewares() {
+		if _, ok := middleware.(*kiotahttp.RetryHandler); ok {
+			continue
+		}
+		middlewares = append(middlewares, middleware)
+	}
+	return middlewares
+}
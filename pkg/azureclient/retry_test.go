@@ -0,0 +1,75 @@
+package azureclient_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/azureclient"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryTransportHonorsRetryAfter(t *testing.T) {
+	assert := require.New(t)
+
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transport := azureclient.NewRetryTransport(nil, azureclient.RetryOptions{})
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+	assert.NoError(err)
+	resp.Body.Close()
+
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal([]string{"payload", "payload", "payload"}, bodies)
+	assert.Equal(int32(2), transport.Retries())
+}
+
+func TestRetryTransportLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  azureclient.RetryOptions
+		code     int
+		expected int
+	}{
+		{"retries are capped", azureclient.RetryOptions{MaxRetries: 2, BaseDelay: time.Millisecond}, http.StatusServiceUnavailable, 3},
+		{"retries can be disabled", azureclient.RetryOptions{MaxRetries: -1}, http.StatusGatewayTimeout, 1},
+		{"other errors are not retried", azureclient.RetryOptions{BaseDelay: time.Millisecond}, http.StatusInternalServerError, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			assert := require.New(tt)
+
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.WriteHeader(test.code)
+			}))
+			defer server.Close()
+
+			client := &http.Client{Transport: azureclient.NewRetryTransport(nil, test.options)}
+			resp, err := client.Get(server.URL)
+			assert.NoError(err)
+			resp.Body.Close()
+
+			assert.Equal(test.code, resp.StatusCode)
+			assert.Equal(test.expected, calls)
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package azureclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// OAuth2 error codes returned by the Azure AD token endpoint.
+const (
+	TokenErrorInvalidGrant        = "invalid_grant"
+	TokenErrorInteractionRequired = "interaction_required"
+	TokenErrorConsentRequired     = "consent_required"
+)
+
+// maxTokenResponseSize bounds the token endpoint responses read into memory.
+const maxTokenResponseSize = 1 << 20
+
+// tokenResponse is the body of an OAuth2 token endpoint response, successful or not.
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+	// ExpiresIn is a number of seconds, sent as a string by some endpoints.
+	ExpiresIn json.Number `json:"expires_in"`
+
+	Error            string `json:"error"`
+	SubError         string `json:"suberror"`
+	ErrorDescription string `json:"error_description"`
+	ErrorCodes       []int  `json:"error_codes"`
+	TraceID          string `json:"trace_id"`
+	CorrelationID    string `json:"correlation_id"`
+}
+
+// TokenError is an error response of the Azure AD token endpoint.
+type TokenError struct {
+	// StatusCode is the HTTP status of the response.
+	StatusCode int
+	// Code is the OAuth2 error code, such as invalid_grant.
+	Code string
+	// SubError refines Code, such as consent_required for an invalid_grant.
+	SubError string
+	// Description is the error description, starting with its AADSTS code.
+	Description string
+	// AADSTSCodes are the numeric AADSTS error codes.
+	AADSTSCodes []int
+	// CorrelationID identifies the request in Azure AD sign-in logs.
+	CorrelationID string
+}
+
+func (e *TokenError) Error() string {
+	message := e.Code
+	if message == "" {
+		message = fmt.Sprintf("token request failed with status %d", e.StatusCode)
+	}
+	if e.Description != "" {
+		// the description carries the trace and correlation ids on separate lines
+		description, _, _ := strings.Cut(e.Description, "\n")
+		message += ": " + strings.TrimSpace(description)
+	}
+	return message
+}
+
+// GRPCStatus maps the error onto the gRPC status reported to the plugin host.
+func (e *TokenError) GRPCStatus() *status.Status {
+	switch e.reason() {
+	case TokenErrorConsentRequired:
+		return status.Newf(codes.PermissionDenied, "the application was not granted consent to the requested permissions: %s", e.Error())
+	case TokenErrorInteractionRequired:
+		return status.Newf(codes.FailedPrecondition, "the user must sign in interactively, for example to satisfy multi-factor authentication: %s", e.Error())
+	case TokenErrorInvalidGrant:
+		return status.Newf(codes.Unauthenticated, "the refresh token is invalid, expired or revoked; sign in again to obtain a new one: %s", e.Error())
+	}
+
+	switch {
+	case e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError:
+		return status.New(codes.Unavailable, e.Error())
+	case e.StatusCode == http.StatusUnauthorized:
+		return status.New(codes.Unauthenticated, e.Error())
+	default:
+		return status.New(codes.InvalidArgument, e.Error())
+	}
+}
+
+// reason returns the most specific of the error code and sub-error, as Azure AD reports missing consent
+// as an invalid_grant with a consent_required sub-error.
+func (e *TokenError) reason() string {
+	switch e.SubError {
+	case TokenErrorConsentRequired, TokenErrorInteractionRequired:
+		return e.SubError
+	}
+	return e.Code
+}
+
+// parseTokenResponse reads a token endpoint response into an access token and the rotated refresh token.
+func parseTokenResponse(res *http.Response) (azcore.AccessToken, string, error) {
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxTokenResponseSize))
+	if err != nil {
+		return azcore.AccessToken{}, "", status.Errorf(codes.Unavailable, "failed to read token response: %s", err.Error())
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		if res.StatusCode != http.StatusOK {
+			return azcore.AccessToken{}, "", &TokenError{StatusCode: res.StatusCode}
+		}
+		return azcore.AccessToken{}, "", status.Errorf(codes.Internal, "failed to parse token response: %s", err.Error())
+	}
+
+	if res.StatusCode != http.StatusOK || token.Error != "" {
+		return azcore.AccessToken{}, "", &TokenError{
+			StatusCode:    res.StatusCode,
+			Code:          token.Error,
+			SubError:      token.SubError,
+			Description:   token.ErrorDescription,
+			AADSTSCodes:   token.ErrorCodes,
+			CorrelationID: token.CorrelationID,
+		}
+	}
+
+	if token.AccessToken == "" {
+		return azcore.AccessToken{}, "", status.Error(codes.Internal, "the token response has no access token")
+	}
+	expiresIn, err := token.ExpiresIn.Int64()
+	if err != nil || expiresIn <= 0 {
+		return azcore.AccessToken{}, "", status.Errorf(codes.Internal, "the token response has an invalid expires_in %q", token.ExpiresIn)
+	}
+
+	return azcore.AccessToken{
+		Token:     token.AccessToken,
+		ExpiresOn: time.Now().Add(time.Duration(expiresIn) * time.Second),
+	}, token.RefreshToken, nil
+}
@@ -0,0 +1,57 @@
+package azureclient
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RefreshTokenStore persists the refresh tokens Azure AD rotates, so that the next run starts from the latest one.
+type RefreshTokenStore interface {
+	// Load returns the saved refresh token, or an empty string if none was saved yet.
+	Load() (string, error)
+	// Save replaces the saved refresh token.
+	Save(refreshToken string) error
+}
+
+// FileRefreshTokenStore keeps the refresh token in a file readable by its owner only.
+type FileRefreshTokenStore struct {
+	Path string
+}
+
+type refreshTokenState struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// Load returns the refresh token saved in the file, or an empty string if the file does not exist.
+func (s *FileRefreshTokenStore) Load() (string, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to read refresh token file %s: %s", s.Path, err.Error())
+	}
+
+	var state refreshTokenState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return "", status.Errorf(codes.Internal, "failed to parse refresh token file %s: %s", s.Path, err.Error())
+	}
+	return state.RefreshToken, nil
+}
+
+// Save atomically replaces the file with the given refresh token.
+func (s *FileRefreshTokenStore) Save(refreshToken string) error {
+	data, err := json.Marshal(refreshTokenState{RefreshToken: refreshToken})
+	if err != nil {
+		return err
+	}
+
+	if err := WriteFileAtomic(s.Path, data); err != nil {
+		return status.Errorf(codes.Internal, "failed to write refresh token file %s: %s", s.Path, err.Error())
+	}
+	return nil
+}
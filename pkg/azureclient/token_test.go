@@ -0,0 +1,145 @@
+package azureclient_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/azureclient"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRefreshTokenResponses(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		code       codes.Code
+		message    string
+	}{
+		{
+			name:       "expired refresh token",
+			statusCode: http.StatusBadRequest,
+			body:       `{"error":"invalid_grant","error_description":"AADSTS700082: The refresh token has expired due to inactivity.\r\nTrace ID: 1\r\nCorrelation ID: 2","error_codes":[700082]}`,
+			code:       codes.Unauthenticated,
+			message:    "AADSTS700082: The refresh token has expired due to inactivity.",
+		},
+		{
+			name:       "interaction required",
+			statusCode: http.StatusBadRequest,
+			body:       `{"error":"interaction_required","error_description":"AADSTS50076: you must use multi-factor authentication.","error_codes":[50076]}`,
+			code:       codes.FailedPrecondition,
+			message:    "AADSTS50076",
+		},
+		{
+			name:       "consent required",
+			statusCode: http.StatusBadRequest,
+			body:       `{"error":"invalid_grant","suberror":"consent_required","error_description":"AADSTS65001: The user or administrator has not consented.","error_codes":[65001]}`,
+			code:       codes.PermissionDenied,
+			message:    "AADSTS65001",
+		},
+		{
+			name:       "invalid client",
+			statusCode: http.StatusUnauthorized,
+			body:       `{"error":"invalid_client","error_description":"AADSTS7000215: Invalid client secret provided."}`,
+			code:       codes.Unauthenticated,
+			message:    "invalid_client: AADSTS7000215",
+		},
+		{
+			name:       "unavailable",
+			statusCode: http.StatusBadGateway,
+			body:       `<html>bad gateway</html>`,
+			code:       codes.Unavailable,
+			message:    "status 502",
+		},
+		{
+			name:       "missing access token",
+			statusCode: http.StatusOK,
+			body:       `{"token_type":"Bearer","expires_in":3600}`,
+			code:       codes.Internal,
+			message:    "no access token",
+		},
+		{
+			name:       "invalid expiry",
+			statusCode: http.StatusOK,
+			body:       `{"access_token":"access","expires_in":0}`,
+			code:       codes.Internal,
+			message:    "invalid expires_in",
+		},
+		{
+			name:       "not json",
+			statusCode: http.StatusOK,
+			body:       `access`,
+			code:       codes.Internal,
+			message:    "failed to parse token response",
+		},
+		{
+			name:       "expiry as string",
+			statusCode: http.StatusOK,
+			body:       `{"access_token":"access","token_type":"Bearer","expires_in":"3600"}`,
+			code:       codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			fake := newFakeAzure(t, "access")
+			fake.token = func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.body))
+			}
+
+			client, err := azureclient.NewClient(context.Background(), refreshTokenCredentials(nil),
+				&azureclient.Options{HTTPClient: fake.client(), Retry: azureclient.RetryOptions{MaxRetries: -1}})
+			assert.NoError(err)
+
+			_, err = client.ListUsers()
+			if tt.code == codes.OK {
+				assert.NoError(err)
+				return
+			}
+			assert.Error(err)
+
+			var tokenErr *azureclient.TokenError
+			if errors.As(err, &tokenErr) {
+				err = tokenErr
+			}
+			s, ok := status.FromError(err)
+			assert.True(ok, "not a status error: %v", err)
+			assert.Equal(tt.code, s.Code(), s.Message())
+			assert.Contains(s.Message(), tt.message)
+		})
+	}
+}
+
+func TestRefreshTokenRequestIsFormEncoded(t *testing.T) {
+	assert := require.New(t)
+
+	fake := newFakeAzure(t, "access")
+	token := fake.token
+	fake.token = func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("application/x-www-form-urlencoded", r.Header.Get("Content-Type"))
+		assert.NoError(r.ParseForm())
+		assert.Equal("refresh_token", r.PostForm.Get("grant_type"))
+		assert.Equal("s&cret=+/", r.PostForm.Get("client_secret"))
+		assert.Equal("0.AR&token=", r.PostForm.Get("refresh_token"))
+		assert.Equal("https://graph.microsoft.com/.default", r.PostForm.Get("scope"))
+		token(w, r)
+	}
+
+	credentials := refreshTokenCredentials(nil)
+	credentials.ClientSecret = "s&cret=+/"
+	credentials.RefreshToken = "0.AR&token="
+	client, err := azureclient.NewClient(context.Background(), credentials, &azureclient.Options{HTTPClient: fake.client()})
+	assert.NoError(err)
+
+	_, err = client.ListUsers()
+	assert.NoError(err)
+}
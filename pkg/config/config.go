@@ -2,9 +2,16 @@ package config
 
 import (
 	"context"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/azureclient"
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/transform"
 	"github.com/aserto-dev/idp-plugin-sdk/plugin"
+	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -20,6 +27,37 @@ func GetVersion() (string, string, string) {
 	return ver, date, commit
 }
 
+const (
+	// DeleteModeSoft moves deleted users to the directory's deleted items, from where they can be restored.
+	DeleteModeSoft = "soft"
+	// DeleteModePurge deletes users and then permanently removes them from the directory's deleted items.
+	DeleteModePurge = "purge"
+	// DeleteModeDisable keeps users in the directory but blocks their sign-in.
+	DeleteModeDisable = "disable"
+	// DeleteModeRestore reverts a soft delete or disable: deleted users are restored from the directory's deleted
+	// items and users that are not there are re-enabled. Purged users cannot be restored.
+	DeleteModeRestore = "restore"
+)
+
+const (
+	// GroupRolesDirect maps the groups a user is a direct member of into roles.
+	GroupRolesDirect = "direct"
+	// GroupRolesTransitive maps the groups a user is a direct or nested member of into roles.
+	GroupRolesTransitive = "transitive"
+)
+
+const (
+	// PhotosDataURI embeds the profile photos of users into their picture as data URIs.
+	PhotosDataURI = "data-uri"
+	// PhotosDirectory writes the profile photos of users into a local directory and links them from their picture.
+	PhotosDirectory = "directory"
+	// PhotoFilePlaceholder is replaced by the name of a photo file in PhotoURLTemplate.
+	PhotoFilePlaceholder = "{file}"
+
+	defaultPhotoSize        = "240x240"
+	defaultPhotoConcurrency = 4
+)
+
 type AzureADConfig struct {
 	Tenant       string `description:"AzureAD tenant" kind:"attribute" mode:"normal" readonly:"false" name:"tenant"`
 	ClientID     string `description:"AzureAD Client ID" kind:"attribute" mode:"normal" readonly:"false" name:"client-id"`
@@ -27,46 +65,212 @@ type AzureADConfig struct {
 	RefreshToken string `description:"AzureAD Refresh Token" kind:"attribute" mode:"normal" readonly:"false" name:"refresh-token"`
 	UserPID      string `description:"AzureAD User PID of the user you want to read" kind:"attribute" mode:"normal" readonly:"false" name:"user-pid"`
 	UserEmail    string `description:"AzureAD User email of the user you want to read" kind:"attribute" mode:"normal" readonly:"false" name:"user-email"`
+
+	ClientCertificate         string `description:"AzureAD client certificate used instead of a client secret: a PEM or PFX file path, inline PEM or base64 PFX" kind:"secret" mode:"masked" readonly:"false" name:"client-certificate"`
+	ClientCertificatePassword string `description:"AzureAD password of the client certificate private key" kind:"secret" mode:"masked" readonly:"false" name:"client-certificate-password"`
+	SendCertificateChain      bool   `description:"AzureAD send the certificate chain with token requests, for subject name and issuer authentication" kind:"attribute" mode:"normal" readonly:"false" name:"send-certificate-chain"`
+
+	RefreshTokenFile string `description:"AzureAD file storing the refresh tokens Azure AD rotates; once saved, it takes precedence over the configured refresh token" kind:"attribute" mode:"normal" readonly:"false" name:"refresh-token-file"`
+
+	CredentialKind     string `description:"AzureAD credential kind: client-secret, certificate, refresh-token, workload-identity or managed-identity; inferred from the other credentials when empty" kind:"attribute" mode:"normal" readonly:"false" name:"credential-kind"`
+	FederatedTokenFile string `description:"AzureAD file holding the federated token of the workload identity; AZURE_FEDERATED_TOKEN_FILE when empty" kind:"attribute" mode:"normal" readonly:"false" name:"federated-token-file"`
+
+	Cloud         string `description:"AzureAD cloud: public (default), usgov, china or custom" kind:"attribute" mode:"normal" readonly:"false" name:"cloud"`
+	AuthorityHost string `description:"AzureAD login endpoint of the custom cloud, such as https://login.microsoftonline.com" kind:"attribute" mode:"normal" readonly:"false" name:"authority-host"`
+	GraphEndpoint string `description:"AzureAD Graph endpoint of the custom cloud, without the API version, such as https://graph.microsoft.com" kind:"attribute" mode:"normal" readonly:"false" name:"graph-endpoint"`
+
+	UserDomain          string `description:"AzureAD verified domain used for the userPrincipalName of created users" kind:"attribute" mode:"normal" readonly:"false" name:"user-domain"`
+	InitialPassword     string `description:"AzureAD initial password of created users; a random one is generated when empty" kind:"secret" mode:"masked" readonly:"false" name:"initial-password"`
+	ForceChangePassword bool   `description:"AzureAD require created users to change their password at next sign-in" kind:"attribute" mode:"normal" readonly:"false" name:"force-change-password"`
+
+	DeleteMode string `description:"AzureAD delete mode: soft (default), purge, disable, or restore to revert a soft delete or disable" kind:"attribute" mode:"normal" readonly:"false" name:"delete-mode"`
+
+	DeltaStateFile string `description:"AzureAD file storing the delta link between reads; when set, only users changed since the previous read are returned" kind:"attribute" mode:"normal" readonly:"false" name:"delta-state-file"`
+
+	UserFilter string `description:"AzureAD OData $filter restricting the users read, such as department eq 'Sales'" kind:"attribute" mode:"normal" readonly:"false" name:"user-filter"`
+	Search     string `description:"AzureAD $search restricting the users read, such as \"displayName:alice\"; sent as an advanced query" kind:"attribute" mode:"normal" readonly:"false" name:"search"`
+
+	Groups string `description:"AzureAD comma separated ids or display names of the groups whose members, including those of nested groups, are read" kind:"attribute" mode:"normal" readonly:"false" name:"groups"`
+
+	AppAssignment string `description:"AzureAD app id or object id of the enterprise application whose assigned users, directly or through groups, are read" kind:"attribute" mode:"normal" readonly:"false" name:"app-assignment"`
+
+	IncludeDisabled        bool `description:"AzureAD read the users whose account is disabled; unlike earlier versions, they are left out unless this is set, and counted in the summary logged at close" kind:"attribute" mode:"normal" readonly:"false" name:"include-disabled"`
+	IncludeGuests          bool `description:"AzureAD read the B2B guest users; unlike earlier versions, they are left out unless this is set, and counted in the summary logged at close" kind:"attribute" mode:"normal" readonly:"false" name:"include-guests"`
+	IncludeServiceAccounts bool `description:"AzureAD read the service accounts: the resource accounts reported by Graph and the users matching the service account pattern; they are left out unless this is set, and counted in the summary logged at close" kind:"attribute" mode:"normal" readonly:"false" name:"include-service-accounts"`
+
+	ServiceAccountPattern string `description:"AzureAD regular expression matching the user principal names of service accounts, such as ^svc-; no user is matched by name when unset" kind:"attribute" mode:"normal" readonly:"false" name:"service-account-pattern"`
+
+	GroupRoles        string `description:"AzureAD group memberships mapped into roles: direct or transitive; disabled when empty" kind:"attribute" mode:"normal" readonly:"false" name:"group-roles"`
+	GroupRoleProperty string `description:"AzureAD group property used as role name: displayName (default), id or mailNickname" kind:"attribute" mode:"normal" readonly:"false" name:"group-role-property"`
+	GroupRoleFilter   string `description:"AzureAD regular expression role names must match to be kept" kind:"attribute" mode:"normal" readonly:"false" name:"group-role-filter"`
+
+	DisplayNameFallbacks string `description:"AzureAD comma separated sources of the display name of users without one: name (given name and surname), upn or mail; all of them in this order when empty" kind:"attribute" mode:"normal" readonly:"false" name:"display-name-fallbacks"`
+
+	IdentitySources string `description:"AzureAD comma separated property[:verified|unverified] list of Graph user properties extracted into identities: mail, mobilePhone, userPrincipalName, proxyAddresses, otherMails, employeeId, onPremisesSamAccountName, onPremisesUserPrincipalName, onPremisesDistinguishedName or identities; mail and mobilePhone when empty, preceded by identities in b2c mode" kind:"attribute" mode:"normal" readonly:"false" name:"identity-sources"`
+
+	B2C                bool   `description:"AzureAD the tenant is an Azure AD B2C tenant, whose users sign in with the names listed in their identities" kind:"attribute" mode:"normal" readonly:"false" name:"b2c"`
+	B2CExtensionsAppID string `description:"AzureAD app id of the b2c-extensions-app storing the custom user flow attributes; looked up when empty" kind:"attribute" mode:"normal" readonly:"false" name:"b2c-extensions-app-id"`
+	B2CAttributes      string `description:"AzureAD comma separated names of the custom user flow attributes copied into user properties, such as LoyaltyNumber" kind:"attribute" mode:"normal" readonly:"false" name:"b2c-attributes"`
+
+	AttributeMapping string `description:"AzureAD comma separated graphProperty[:property] list of Graph user properties copied into user properties; nested Graph properties are separated by dots" kind:"attribute" mode:"normal" readonly:"false" name:"attribute-mapping"`
+	ExtensionsApp    string `description:"AzureAD object id or app id of the application whose directory extension properties targeting users are copied into user properties named after them" kind:"attribute" mode:"normal" readonly:"false" name:"extensions-app"`
+	ExtensionMapping string `description:"AzureAD comma separated list of extensions copied into user properties: directory:extension_{appId}_{name}[:property], schema:{extensionId}/{property}[:property] or open:{extensionName}/{property}[:property]; open extensions cannot be combined with a delta state file" kind:"attribute" mode:"normal" readonly:"false" name:"extension-mapping"`
+
+	MaxRetries    int `description:"AzureAD retries of throttled or unavailable Graph and token requests; 5 when unset, negative to disable; the retried requests are counted in the summary logged at close" kind:"attribute" mode:"normal" readonly:"false" name:"max-retries"`
+	MaxRetryDelay int `description:"AzureAD longest wait between retries, in seconds; 60 when unset" kind:"attribute" mode:"normal" readonly:"false" name:"max-retry-delay"`
+
+	Photos           string `description:"AzureAD profile photos loaded into user pictures: data-uri or directory; disabled when empty" kind:"attribute" mode:"normal" readonly:"false" name:"photos"`
+	PhotoSize        string `description:"AzureAD size of the profile photos: 48x48, 64x64, 96x96, 120x120, 240x240 (default), 360x360, 432x432, 504x504 or 648x648" kind:"attribute" mode:"normal" readonly:"false" name:"photo-size"`
+	PhotoDirectory   string `description:"AzureAD local directory the profile photos are written to in directory mode" kind:"attribute" mode:"normal" readonly:"false" name:"photo-directory"`
+	PhotoURLTemplate string `description:"AzureAD URL of the photos written in directory mode, where {file} is replaced by the file name; file URLs are used when empty" kind:"attribute" mode:"normal" readonly:"false" name:"photo-url-template"`
+	PhotoConcurrency int    `description:"AzureAD profile photos downloaded at the same time; 4 when unset" kind:"attribute" mode:"normal" readonly:"false" name:"photo-concurrency"`
+
+	AppRoles bool `description:"AzureAD load the app roles assigned to users through enterprise applications into their applications" kind:"attribute" mode:"normal" readonly:"false" name:"app-roles"`
 }
 
 func (c *AzureADConfig) Validate(operation plugin.OperationType) error {
 	var client *azureclient.AzureADClient
 	var err error
 
-	if c.Tenant == "" {
-		return status.Error(codes.InvalidArgument, "no tenant was provided")
+	if err := c.validateCredentials(); err != nil {
+		return err
 	}
 
-	if c.ClientID == "" {
-		return status.Error(codes.InvalidArgument, "no client id was provided")
-	}
-
-	if c.ClientSecret == "" {
-		return status.Error(codes.InvalidArgument, "no client secret was provided")
+	if _, err := azureclient.LookupCloud(c.Cloud, c.AuthorityHost, c.GraphEndpoint); err != nil {
+		return err
 	}
 
 	if c.UserPID != "" && c.UserEmail != "" {
 		return status.Error(codes.InvalidArgument, "an user PID and an user email were provided; please specify only one")
 	}
 
-	if c.RefreshToken != "" {
-		client, err = azureclient.NewAzureADClientWithRefreshToken(
-			context.Background(),
-			c.Tenant,
-			c.ClientID,
-			c.ClientSecret,
-			c.RefreshToken)
-	} else {
-		client, err = azureclient.NewAzureADClient(
-			context.Background(),
-			c.Tenant,
-			c.ClientID,
-			c.ClientSecret)
+	if c.DeltaStateFile != "" && (c.UserPID != "" || c.UserEmail != "") {
+		return status.Error(codes.InvalidArgument, "a delta state file cannot be combined with an user PID or an user email")
+	}
+
+	if c.AppAssignment != "" {
+		if c.UserPID != "" || c.UserEmail != "" {
+			return status.Error(codes.InvalidArgument, "an app assignment cannot be combined with an user PID or an user email")
+		}
+		if c.DeltaStateFile != "" || len(c.GroupRefs()) > 0 || c.UserFilter != "" || c.Search != "" {
+			return status.Error(codes.InvalidArgument, "an app assignment cannot be combined with a delta state file, groups, a user filter or search")
+		}
+	}
+
+	if len(c.GroupRefs()) > 0 {
+		if c.UserPID != "" || c.UserEmail != "" {
+			return status.Error(codes.InvalidArgument, "groups cannot be combined with an user PID or an user email")
+		}
+		if c.DeltaStateFile != "" {
+			return status.Error(codes.InvalidArgument, "groups cannot be combined with a delta state file")
+		}
+		if c.UserFilter != "" || c.Search != "" {
+			return status.Error(codes.InvalidArgument, "groups cannot be combined with a user filter or search")
+		}
+	}
+
+	if c.UserFilter != "" || c.Search != "" {
+		if c.UserPID != "" || c.UserEmail != "" {
+			return status.Error(codes.InvalidArgument, "a user filter or search cannot be combined with an user PID or an user email")
+		}
+		if c.DeltaStateFile != "" {
+			return status.Error(codes.InvalidArgument, "a user filter or search cannot be combined with a delta state file")
+		}
+	}
+
+	switch c.DeleteMode {
+	case "", DeleteModeSoft, DeleteModePurge, DeleteModeDisable, DeleteModeRestore:
+	default:
+		return status.Errorf(codes.InvalidArgument, "invalid delete mode %q; expected %s, %s, %s or %s",
+			c.DeleteMode, DeleteModeSoft, DeleteModePurge, DeleteModeDisable, DeleteModeRestore)
 	}
+
+	switch c.GroupRoles {
+	case "", GroupRolesDirect, GroupRolesTransitive:
+	default:
+		return status.Errorf(codes.InvalidArgument, "invalid group roles %q; expected %s or %s", c.GroupRoles, GroupRolesDirect, GroupRolesTransitive)
+	}
+
+	switch c.GroupRoleProperty {
+	case "", transform.GroupPropertyDisplayName, transform.GroupPropertyID, transform.GroupPropertyMailNickname:
+	default:
+		return status.Errorf(codes.InvalidArgument, "invalid group role property %q; expected %s, %s or %s",
+			c.GroupRoleProperty, transform.GroupPropertyDisplayName, transform.GroupPropertyID, transform.GroupPropertyMailNickname)
+	}
+
+	if _, err := regexp.Compile(c.GroupRoleFilter); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid group role filter: %s", err.Error())
+	}
+
+	if _, err := c.Inclusion(); err != nil {
+		return err
+	}
+
+	if _, err := transform.ParseDisplayNameFallbacks(c.DisplayNameFallbacks); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if _, err := c.IdentitySourceList(); err != nil {
+		return err
+	}
+
+	if err := c.validateB2C(); err != nil {
+		return err
+	}
+
+	if err := c.validatePhotos(); err != nil {
+		return err
+	}
+
+	if c.MaxRetryDelay < 0 {
+		return status.Error(codes.InvalidArgument, "the max retry delay cannot be negative")
+	}
+
+	if _, err := transform.ParseAttributeMapping(c.AttributeMapping); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid attribute mapping: %s", err.Error())
+	}
+
+	if err := c.validateExtensions(); err != nil {
+		return err
+	}
+
+	client, err = c.NewClient(context.Background())
 	if err != nil {
 		return status.Errorf(codes.Internal, "failed to connect to AzureAD, %s", err.Error())
 	}
 
+	for _, ref := range c.GroupRefs() {
+		if _, err := client.ResolveGroup(ref); err != nil {
+			return err
+		}
+	}
+
+	if c.AppAssignment != "" {
+		if _, err := client.ResolveServicePrincipal(c.AppAssignment); err != nil {
+			return err
+		}
+	}
+
+	if c.B2C && len(c.B2CAttributeNames()) > 0 && c.B2CExtensionsAppID == "" {
+		if _, err := client.FindB2CExtensionsApp(); err != nil {
+			return err
+		}
+	}
+
+	if c.ExtensionsApp != "" {
+		if _, err := client.ResolveApplication(c.ExtensionsApp); err != nil {
+			return err
+		}
+	}
+
+	if c.UserFilter != "" || c.Search != "" {
+		// a single user is enough for Graph to reject an invalid filter or search
+		if err := client.ProbeUsers(c.UserQuery()); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid user filter or search: %s", azureclient.GraphErrorMessage(err))
+		}
+		return nil
+	}
+
 	_, errReq := client.ListUsers()
 
 	if errReq != nil {
@@ -76,6 +280,278 @@ func (c *AzureADConfig) Validate(operation plugin.OperationType) error {
 	return nil
 }
 
+func (c *AzureADConfig) validateB2C() error {
+	if !c.B2C {
+		if c.B2CExtensionsAppID != "" || c.B2CAttributes != "" {
+			return status.Error(codes.InvalidArgument, "a b2c-extensions-app id or b2c attributes require b2c to be enabled")
+		}
+		return nil
+	}
+
+	// the app id is looked up by Validate when empty
+	appID := c.B2CExtensionsAppID
+	if appID == "" {
+		appID = uuid.Nil.String()
+	}
+	mapping, err := transform.B2CExtensionMapping(appID, c.B2CAttributeNames())
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid b2c attributes: %s", err.Error())
+	}
+	attributes, _ := transform.ParseAttributeMapping(c.AttributeMapping)
+	if _, err := attributes.With(mapping); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid b2c attributes: %s", err.Error())
+	}
+	return nil
+}
+
+func (c *AzureADConfig) validateExtensions() error {
+	extensions, err := transform.ParseExtensionMapping(c.ExtensionMapping)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid extension mapping: %s", err.Error())
+	}
+	if len(extensions.Expand()) > 0 && c.DeltaStateFile != "" {
+		return status.Error(codes.InvalidArgument, "open extensions cannot be combined with a delta state file")
+	}
+	attributes, _ := transform.ParseAttributeMapping(c.AttributeMapping)
+	if _, err := attributes.With(extensions); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid extension mapping: %s", err.Error())
+	}
+	return nil
+}
+
+func (c *AzureADConfig) validatePhotos() error {
+	switch c.Photos {
+	case "":
+		return nil
+	case PhotosDataURI:
+	case PhotosDirectory:
+		if c.PhotoDirectory == "" {
+			return status.Error(codes.InvalidArgument, "no photo directory was provided")
+		}
+		if c.PhotoURLTemplate != "" && !strings.Contains(c.PhotoURLTemplate, PhotoFilePlaceholder) {
+			return status.Errorf(codes.InvalidArgument, "the photo URL template must contain %s", PhotoFilePlaceholder)
+		}
+	default:
+		return status.Errorf(codes.InvalidArgument, "invalid photos %q; expected %s or %s", c.Photos, PhotosDataURI, PhotosDirectory)
+	}
+
+	if !slices.Contains(azureclient.PhotoSizes, c.PhotoSizeOrDefault()) {
+		return status.Errorf(codes.InvalidArgument, "invalid photo size %q; expected one of %s",
+			c.PhotoSize, strings.Join(azureclient.PhotoSizes, ", "))
+	}
+	if c.PhotoConcurrency < 0 {
+		return status.Error(codes.InvalidArgument, "the photo concurrency cannot be negative")
+	}
+	return nil
+}
+
+func (c *AzureADConfig) validateCredentials() error {
+	kind := c.Credentials().Kind
+
+	if c.Tenant == "" && kind != azureclient.CredentialManagedIdentity && kind != azureclient.CredentialWorkloadIdentity {
+		return status.Error(codes.InvalidArgument, "no tenant was provided")
+	}
+
+	if c.ClientID == "" && kind != azureclient.CredentialManagedIdentity && kind != azureclient.CredentialWorkloadIdentity {
+		return status.Error(codes.InvalidArgument, "no client id was provided")
+	}
+
+	switch kind {
+	case azureclient.CredentialClientSecret:
+		if c.ClientSecret == "" {
+			return status.Error(codes.InvalidArgument, "no client secret was provided")
+		}
+	case azureclient.CredentialCertificate:
+		if c.ClientCertificate == "" {
+			return status.Error(codes.InvalidArgument, "no client certificate was provided")
+		}
+		if c.ClientSecret != "" {
+			return status.Error(codes.InvalidArgument, "a client secret and a client certificate were provided; please specify only one")
+		}
+	case azureclient.CredentialRefreshToken:
+		if c.RefreshToken == "" {
+			return status.Error(codes.InvalidArgument, "no refresh token was provided")
+		}
+		if c.ClientSecret == "" {
+			return status.Error(codes.InvalidArgument, "a refresh token requires a client secret")
+		}
+	case azureclient.CredentialWorkloadIdentity:
+		if c.FederatedTokenFile == "" && os.Getenv("AZURE_FEDERATED_TOKEN_FILE") == "" {
+			return status.Error(codes.InvalidArgument, "no federated token file was provided")
+		}
+	case azureclient.CredentialManagedIdentity:
+	default:
+		return status.Errorf(codes.InvalidArgument, "invalid credential kind %q; expected %s, %s, %s, %s or %s", c.CredentialKind,
+			azureclient.CredentialClientSecret, azureclient.CredentialCertificate, azureclient.CredentialRefreshToken,
+			azureclient.CredentialWorkloadIdentity, azureclient.CredentialManagedIdentity)
+	}
+
+	return nil
+}
+
+// Credentials returns the credentials of the config. When no credential kind is set, a refresh token
+// takes precedence over a client certificate, which takes precedence over a client secret.
+// Rotated refresh tokens are saved in the refresh token file, when set.
+func (c *AzureADConfig) Credentials() azureclient.Credentials {
+	kind := azureclient.CredentialKind(c.CredentialKind)
+	if kind == "" {
+		switch {
+		case c.RefreshToken != "":
+			kind = azureclient.CredentialRefreshToken
+		case c.ClientCertificate != "":
+			kind = azureclient.CredentialCertificate
+		default:
+			kind = azureclient.CredentialClientSecret
+		}
+	}
+
+	credentials := azureclient.Credentials{
+		Kind:                 kind,
+		Tenant:               c.Tenant,
+		ClientID:             c.ClientID,
+		ClientSecret:         c.ClientSecret,
+		RefreshToken:         c.RefreshToken,
+		Certificate:          c.ClientCertificate,
+		CertificatePassword:  c.ClientCertificatePassword,
+		SendCertificateChain: c.SendCertificateChain,
+		FederatedTokenFile:   c.FederatedTokenFile,
+	}
+	if c.RefreshTokenFile != "" {
+		credentials.RefreshTokenStore = &azureclient.FileRefreshTokenStore{Path: c.RefreshTokenFile}
+	}
+	return credentials
+}
+
+// NewClient creates a Graph client authenticating with the credentials of the config.
+func (c *AzureADConfig) NewClient(ctx context.Context) (*azureclient.AzureADClient, error) {
+	return azureclient.NewClient(ctx, c.Credentials(), c.ClientOptions())
+}
+
+// UserQuery returns the user filter and search restricting the users read, along with the filters excluding
+// disabled and guest users. Service accounts cannot be filtered by Graph and are excluded by Inclusion.
+func (c *AzureADConfig) UserQuery() azureclient.UserQuery {
+	query := c.baseUserQuery()
+	var exclusions []azureclient.Filter
+	if !c.IncludeDisabled {
+		exclusions = append(exclusions, azureclient.Eq("accountEnabled", true))
+	}
+	if !c.IncludeGuests {
+		exclusions = append(exclusions, azureclient.Eq("userType", transform.UserTypeMember))
+	}
+	query.Filter = azureclient.And(append([]azureclient.Filter{query.Filter}, exclusions...)...)
+	return query
+}
+
+// ExcludedUserQueries returns, by exclusion reason, the queries matching the users UserQuery leaves out.
+// A user both disabled and a guest is only matched by the disabled query.
+func (c *AzureADConfig) ExcludedUserQueries() map[string]azureclient.UserQuery {
+	queries := make(map[string]azureclient.UserQuery)
+	base := c.baseUserQuery()
+
+	if !c.IncludeDisabled {
+		query := base
+		query.Filter = azureclient.And(base.Filter, azureclient.Eq("accountEnabled", false))
+		queries[transform.ExcludedDisabled] = query
+	}
+	if !c.IncludeGuests {
+		query := base
+		var enabled azureclient.Filter
+		if !c.IncludeDisabled {
+			enabled = azureclient.Eq("accountEnabled", true)
+		}
+		query.Filter = azureclient.And(base.Filter, enabled, azureclient.Ne("userType", transform.UserTypeMember))
+		queries[transform.ExcludedGuest] = query
+	}
+	return queries
+}
+
+// Inclusion returns the accounts read besides enabled member users.
+func (c *AzureADConfig) Inclusion() (transform.Inclusion, error) {
+	inclusion := transform.Inclusion{
+		Disabled:        c.IncludeDisabled,
+		Guests:          c.IncludeGuests,
+		ServiceAccounts: c.IncludeServiceAccounts,
+	}
+	if c.IncludeServiceAccounts || c.ServiceAccountPattern == "" {
+		return inclusion, nil
+	}
+
+	var err error
+	if inclusion.ServiceAccountPattern, err = regexp.Compile(c.ServiceAccountPattern); err != nil {
+		return inclusion, status.Errorf(codes.InvalidArgument, "invalid service account pattern: %s", err.Error())
+	}
+	return inclusion, nil
+}
+
+func (c *AzureADConfig) baseUserQuery() azureclient.UserQuery {
+	return azureclient.UserQuery{
+		Filter: azureclient.Raw(c.UserFilter),
+		Search: c.Search,
+	}
+}
+
+// PhotoSizeOrDefault returns the size of the profile photos read.
+func (c *AzureADConfig) PhotoSizeOrDefault() string {
+	if c.PhotoSize == "" {
+		return defaultPhotoSize
+	}
+	return c.PhotoSize
+}
+
+// PhotoConcurrencyOrDefault returns the number of profile photos downloaded at the same time.
+func (c *AzureADConfig) PhotoConcurrencyOrDefault() int {
+	if c.PhotoConcurrency == 0 {
+		return defaultPhotoConcurrency
+	}
+	return c.PhotoConcurrency
+}
+
+// GroupRefs returns the ids or display names of the groups whose members are read.
+func (c *AzureADConfig) GroupRefs() []string {
+	return splitList(c.Groups)
+}
+
+// B2CAttributeNames returns the names of the custom user flow attributes read from a B2C tenant.
+func (c *AzureADConfig) B2CAttributeNames() []string {
+	return splitList(c.B2CAttributes)
+}
+
+// IdentitySourceList returns the user properties extracted into identities. B2C tenants default to
+// the sign-in names of their users.
+func (c *AzureADConfig) IdentitySourceList() (transform.IdentitySources, error) {
+	if c.B2C && strings.TrimSpace(c.IdentitySources) == "" {
+		return transform.DefaultB2CIdentitySources, nil
+	}
+	sources, err := transform.ParseIdentitySources(c.IdentitySources)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid identity sources: %s", err.Error())
+	}
+	return sources, nil
+}
+
+// ClientOptions returns the Graph client options set by the config. An invalid cloud falls back to
+// the public cloud; Validate reports it.
+func (c *AzureADConfig) ClientOptions() *azureclient.Options {
+	cloud, _ := azureclient.LookupCloud(c.Cloud, c.AuthorityHost, c.GraphEndpoint)
+	return &azureclient.Options{
+		Retry: azureclient.RetryOptions{
+			MaxRetries: c.MaxRetries,
+			MaxDelay:   time.Duration(c.MaxRetryDelay) * time.Second,
+		},
+		Cloud: cloud,
+	}
+}
+
 func (c *AzureADConfig) Description() string {
 	return "AzureAD plugin"
 }
+
+func splitList(list string) []string {
+	var values []string
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
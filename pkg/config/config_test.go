@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/config"
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/transform"
 	"github.com/aserto-dev/idp-plugin-sdk/plugin"
 	"github.com/stretchr/testify/require"
 )
@@ -80,6 +81,21 @@ func TestValidateWithUserIDAndEmail(t *testing.T) {
 	assert.Contains(err.Error(), "rpc error: code = InvalidArgument desc = an user PID and an user email were provided; please specify only one")
 }
 
+func TestValidateWithInvalidDeleteMode(t *testing.T) {
+	assert := require.New(t)
+	cfg := config.AzureADConfig{
+		Tenant:       "tenant",
+		ClientID:     "id",
+		ClientSecret: "secret",
+		DeleteMode:   "shred",
+	}
+
+	err := cfg.Validate(plugin.OperationTypeDelete)
+
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "rpc error: code = InvalidArgument desc = invalid delete mode \"shred\"")
+}
+
 func TestDescription(t *testing.T) {
 	assert := require.New(t)
 	cfg := config.AzureADConfig{
@@ -92,3 +108,283 @@ func TestDescription(t *testing.T) {
 
 	assert.Equal("AzureAD plugin", description)
 }
+
+func TestValidateWithCertificateAndNoSecret(t *testing.T) {
+	assert := require.New(t)
+	cfg := config.AzureADConfig{
+		Tenant:            "tenant",
+		ClientID:          "id",
+		ClientCertificate: "-----BEGIN CERTIFICATE-----\n-----END CERTIFICATE-----",
+	}
+
+	err := cfg.Validate(plugin.OperationTypeRead)
+
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "failed to parse client certificate")
+}
+
+func TestValidateWithSecretAndCertificate(t *testing.T) {
+	assert := require.New(t)
+	cfg := config.AzureADConfig{
+		Tenant:            "tenant",
+		ClientID:          "id",
+		ClientSecret:      "secret",
+		ClientCertificate: "client.pem",
+	}
+
+	err := cfg.Validate(plugin.OperationTypeRead)
+
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "a client secret and a client certificate were provided")
+}
+
+func TestValidateWithInvalidCredentialKind(t *testing.T) {
+	assert := require.New(t)
+	cfg := config.AzureADConfig{
+		Tenant:         "tenant",
+		ClientID:       "id",
+		ClientSecret:   "secret",
+		CredentialKind: "password",
+	}
+
+	err := cfg.Validate(plugin.OperationTypeRead)
+
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "invalid credential kind")
+}
+
+func TestValidateWithWorkloadIdentityAndNoTokenFile(t *testing.T) {
+	assert := require.New(t)
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", "")
+	cfg := config.AzureADConfig{
+		CredentialKind: "workload-identity",
+	}
+
+	err := cfg.Validate(plugin.OperationTypeRead)
+
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "no federated token file was provided")
+}
+
+func TestValidateWithCustomCloudAndNoAuthorityHost(t *testing.T) {
+	assert := require.New(t)
+	cfg := config.AzureADConfig{
+		Tenant:        "tenant",
+		ClientID:      "id",
+		ClientSecret:  "secret",
+		Cloud:         "custom",
+		GraphEndpoint: "https://graph.example.com",
+	}
+
+	err := cfg.Validate(plugin.OperationTypeRead)
+
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "no authority host was provided")
+}
+
+func TestValidateWithUserFilterAndUserPID(t *testing.T) {
+	assert := require.New(t)
+	cfg := config.AzureADConfig{
+		Tenant:       "tenant",
+		ClientID:     "id",
+		ClientSecret: "secret",
+		UserPID:      "pid",
+		UserFilter:   "accountEnabled eq true",
+	}
+
+	err := cfg.Validate(plugin.OperationTypeRead)
+
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "a user filter or search cannot be combined with an user PID")
+}
+
+func TestValidateWithGroupsAndDeltaStateFile(t *testing.T) {
+	assert := require.New(t)
+	cfg := config.AzureADConfig{
+		Tenant:         "tenant",
+		ClientID:       "id",
+		ClientSecret:   "secret",
+		Groups:         "Aserto-Users",
+		DeltaStateFile: "delta.json",
+	}
+
+	err := cfg.Validate(plugin.OperationTypeRead)
+
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "groups cannot be combined with a delta state file")
+}
+
+func TestValidateWithAppAssignmentAndGroups(t *testing.T) {
+	assert := require.New(t)
+	cfg := config.AzureADConfig{
+		Tenant:        "tenant",
+		ClientID:      "id",
+		ClientSecret:  "secret",
+		AppAssignment: "7b1d2c4e-0000-4000-8000-000000000001",
+		Groups:        "Aserto-Users",
+	}
+
+	err := cfg.Validate(plugin.OperationTypeRead)
+
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "an app assignment cannot be combined")
+}
+
+func TestUserQueryExclusions(t *testing.T) {
+	assert := require.New(t)
+	cfg := config.AzureADConfig{UserFilter: "department eq 'Sales' or department eq 'IT'"}
+
+	filter, err := cfg.UserQuery().Filter.Build()
+	assert.NoError(err)
+	assert.Equal("(department eq 'Sales' or department eq 'IT') and accountEnabled eq true and userType eq 'Member'", filter)
+
+	excluded := cfg.ExcludedUserQueries()
+	assert.Len(excluded, 2)
+	guests, err := excluded["guest"].Filter.Build()
+	assert.NoError(err)
+	assert.Equal("(department eq 'Sales' or department eq 'IT') and accountEnabled eq true and userType ne 'Member'", guests)
+
+	cfg.IncludeDisabled = true
+	cfg.IncludeGuests = true
+	filter, err = cfg.UserQuery().Filter.Build()
+	assert.NoError(err)
+	assert.Equal("department eq 'Sales' or department eq 'IT'", filter)
+	assert.Empty(cfg.ExcludedUserQueries())
+}
+
+func TestInclusionServiceAccountPattern(t *testing.T) {
+	assert := require.New(t)
+	cfg := config.AzureADConfig{
+		Tenant:       "tenant",
+		ClientID:     "id",
+		ClientSecret: "secret",
+	}
+
+	inclusion, err := cfg.Inclusion()
+	assert.NoError(err)
+	assert.Nil(inclusion.ServiceAccountPattern, "no user is matched by name unless a pattern is set")
+
+	cfg.ServiceAccountPattern = `^app\.`
+	inclusion, err = cfg.Inclusion()
+	assert.NoError(err)
+	assert.True(inclusion.ServiceAccountPattern.MatchString("app.billing@contoso.onmicrosoft.com"))
+
+	cfg.IncludeServiceAccounts = true
+	inclusion, err = cfg.Inclusion()
+	assert.NoError(err)
+	assert.Nil(inclusion.ServiceAccountPattern)
+
+	cfg.IncludeServiceAccounts = false
+	cfg.ServiceAccountPattern = "^(svc"
+	err = cfg.Validate(plugin.OperationTypeRead)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "invalid service account pattern")
+}
+
+func TestValidatePhotos(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.AzureADConfig
+		err  string
+	}{
+		{name: "invalid mode", cfg: config.AzureADConfig{Photos: "inline"}, err: "invalid photos"},
+		{name: "invalid size", cfg: config.AzureADConfig{Photos: config.PhotosDataURI, PhotoSize: "100x100"}, err: "invalid photo size"},
+		{name: "no directory", cfg: config.AzureADConfig{Photos: config.PhotosDirectory}, err: "no photo directory was provided"},
+		{name: "template without file", cfg: config.AzureADConfig{Photos: config.PhotosDirectory, PhotoDirectory: "photos", PhotoURLTemplate: "https://cdn.test.com/photos"}, err: "must contain {file}"},
+		{name: "negative concurrency", cfg: config.AzureADConfig{Photos: config.PhotosDataURI, PhotoConcurrency: -1}, err: "cannot be negative"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+			tt.cfg.Tenant, tt.cfg.ClientID, tt.cfg.ClientSecret = "tenant", "id", "secret"
+
+			err := tt.cfg.Validate(plugin.OperationTypeRead)
+
+			assert.Error(err)
+			assert.Contains(err.Error(), tt.err)
+		})
+	}
+}
+
+func TestValidateWithInvalidDisplayNameFallback(t *testing.T) {
+	assert := require.New(t)
+	cfg := config.AzureADConfig{
+		Tenant:               "tenant",
+		ClientID:             "id",
+		ClientSecret:         "secret",
+		DisplayNameFallbacks: "name,nickname",
+	}
+
+	err := cfg.Validate(plugin.OperationTypeRead)
+
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "invalid display name fallback")
+}
+
+func TestValidateB2C(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.AzureADConfig
+		err  string
+	}{
+		{name: "attributes without b2c", cfg: config.AzureADConfig{B2CAttributes: "LoyaltyNumber"}, err: "require b2c to be enabled"},
+		{name: "invalid app id", cfg: config.AzureADConfig{B2C: true, B2CExtensionsAppID: "b2c-extensions-app", B2CAttributes: "LoyaltyNumber"}, err: "invalid b2c-extensions-app id"},
+		{name: "invalid attribute", cfg: config.AzureADConfig{B2C: true, B2CAttributes: "Loyalty Number"}, err: "invalid attribute name"},
+		{name: "attribute mapped twice", cfg: config.AzureADConfig{B2C: true, B2CAttributes: "department", AttributeMapping: "department"}, err: "mapped more than once"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+			tt.cfg.Tenant, tt.cfg.ClientID, tt.cfg.ClientSecret = "tenant", "id", "secret"
+
+			err := tt.cfg.Validate(plugin.OperationTypeRead)
+
+			assert.Error(err)
+			assert.Contains(err.Error(), tt.err)
+		})
+	}
+}
+
+func TestValidateExtensionMapping(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.AzureADConfig
+		err  string
+	}{
+		{name: "unknown kind", cfg: config.AzureADConfig{ExtensionMapping: "custom:contoso/level"}, err: "invalid extension kind"},
+		{name: "invalid directory extension", cfg: config.AzureADConfig{ExtensionMapping: "directory:costCenter"}, err: "invalid directory extension property"},
+		{name: "open extension with delta", cfg: config.AzureADConfig{ExtensionMapping: "open:com.contoso.settings/theme", DeltaStateFile: "delta.json"}, err: "cannot be combined with a delta state file"},
+		{name: "property mapped twice", cfg: config.AzureADConfig{ExtensionMapping: "schema:contoso_employee/level:department", AttributeMapping: "department"}, err: "mapped more than once"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+			tt.cfg.Tenant, tt.cfg.ClientID, tt.cfg.ClientSecret = "tenant", "id", "secret"
+
+			err := tt.cfg.Validate(plugin.OperationTypeRead)
+
+			assert.Error(err)
+			assert.Contains(err.Error(), tt.err)
+		})
+	}
+}
+
+func TestIdentitySourceListDefaultsToSignInsInB2C(t *testing.T) {
+	assert := require.New(t)
+	cfg := config.AzureADConfig{B2C: true}
+
+	sources, err := cfg.IdentitySourceList()
+	assert.NoError(err)
+	assert.Equal(transform.DefaultB2CIdentitySources, sources)
+
+	cfg.IdentitySources = "mail"
+	sources, err = cfg.IdentitySourceList()
+	assert.NoError(err)
+	assert.Equal(transform.IdentitySources{{Property: "mail", Verified: true}}, sources)
+}
@@ -0,0 +1,96 @@
+package item
+
+import (
+    "context"
+    i2ae4187f7daee263371cb1c977df639813ab50ffa529013b7437480d1ec0158f "github.com/microsoft/kiota-abstractions-go"
+    i0ce6dbcacbf3c79ca963160a4abb755a6a06643231b513d9e0f9a89464cc184b "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/models"
+    i0add6a40679c106013e9da46a5d72e4fa956366e39a3307fa519ccb21a5fcf80 "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/models/odataerrors"
+    iceaafc4e898afe2a69196b8ae186ae4582e3b2c890434769e854bca5b7048d3c "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/applications/item/extensionproperties"
+)
+
+// ApplicationItemRequestBuilder builds and executes requests for operations under \applications\{application-id}
+type ApplicationItemRequestBuilder struct {
+    // Path parameters for the request
+    pathParameters map[string]string
+    // The request adapter to use to execute the requests.
+    requestAdapter i2ae4187f7daee263371cb1c977df639813ab50ffa529013b7437480d1ec0158f.RequestAdapter
+    // Url template to use to build the URL for the current request builder
+    urlTemplate string
+}
+// ApplicationItemRequestBuilderGetQueryParameters get the properties and relationships of an application object.
+type ApplicationItemRequestBuilderGetQueryParameters struct {
+    // Expand related entities
+    Expand []string `uriparametername:"%24expand"`
+    // Select properties to be returned
+    Select []string `uriparametername:"%24select"`
+}
+// ApplicationItemRequestBuilderGetRequestConfiguration configuration for the request such as headers, query parameters, and middleware options.
+type ApplicationItemRequestBuilderGetRequestConfiguration struct {
+    // Request headers
+    Headers *i2ae4187f7daee263371cb1c977df639813ab50ffa529013b7437480d1ec0158f.RequestHeaders
+    // Request options
+    Options []i2ae4187f7daee263371cb1c977df639813ab50ffa529013b7437480d1ec0158f.RequestOption
+    // Request query parameters
+    QueryParameters *ApplicationItemRequestBuilderGetQueryParameters
+}
+// NewApplicationItemRequestBuilderInternal instantiates a new ApplicationItemRequestBuilder and sets the default values.
+func NewApplicationItemRequestBuilderInternal(pathParameters map[string]string, requestAdapter i2ae4187f7daee263371cb1c977df639813ab50ffa529013b7437480d1ec0158f.RequestAdapter)(*ApplicationItemRequestBuilder) {
+    m := &ApplicationItemRequestBuilder{
+    }
+    m.urlTemplate = "{+baseurl}/applications/{application%2Did}{?%24select,%24expand}";
+    urlTplParams := make(map[string]string)
+    for idx, item := range pathParameters {
+        urlTplParams[idx] = item
+    }
+    m.pathParameters = urlTplParams
+    m.requestAdapter = requestAdapter
+    return m
+}
+// NewApplicationItemRequestBuilder instantiates a new ApplicationItemRequestBuilder and sets the default values.
+func NewApplicationItemRequestBuilder(rawUrl string, requestAdapter i2ae4187f7daee263371cb1c977df639813ab50ffa529013b7437480d1ec0158f.RequestAdapter)(*ApplicationItemRequestBuilder) {
+    urlParams := make(map[string]string)
+    urlParams["request-raw-url"] = rawUrl
+    return NewApplicationItemRequestBuilderInternal(urlParams, requestAdapter)
+}
+// ExtensionProperties provides operations to manage the extensionProperties property of the microsoft.graph.application entity.
+func (m *ApplicationItemRequestBuilder) ExtensionProperties()(*iceaafc4e898afe2a69196b8ae186ae4582e3b2c890434769e854bca5b7048d3c.ExtensionPropertiesRequestBuilder) {
+    return iceaafc4e898afe2a69196b8ae186ae4582e3b2c890434769e854bca5b7048d3c.NewExtensionPropertiesRequestBuilderInternal(m.pathParameters, m.requestAdapter);
+}
+// Get get the properties and relationships of an application object.
+// [Find more info here]
+// 
+// [Find more info here]: https://docs.microsoft.com/graph/api/application-get?view=graph-rest-1.0
+func (m *ApplicationItemRequestBuilder) Get(ctx context.Context, requestConfiguration *ApplicationItemRequestBuilderGetRequestConfiguration)(i0ce6dbcacbf3c79ca963160a4abb755a6a06643231b513d9e0f9a89464cc184b.Applicationable, error) {
+    requestInfo, err := m.ToGetRequestInformation(ctx, requestConfiguration);
+    if err != nil {
+        return nil, err
+    }
+    errorMapping := i2ae4187f7daee263371cb1c977df639813ab50ffa529013b7437480d1ec0158f.ErrorMappings {
+        "4XX": i0add6a40679c106013e9da46a5d72e4fa956366e39a3307fa519ccb21a5fcf80.CreateODataErrorFromDiscriminatorValue,
+        "5XX": i0add6a40679c106013e9da46a5d72e4fa956366e39a3307fa519ccb21a5fcf80.CreateODataErrorFromDiscriminatorValue,
+    }
+    res, err := m.requestAdapter.Send(ctx, requestInfo, i0ce6dbcacbf3c79ca963160a4abb755a6a06643231b513d9e0f9a89464cc184b.CreateApplicationFromDiscriminatorValue, errorMapping)
+    if err != nil {
+        return nil, err
+    }
+    if res == nil {
+        return nil, nil
+    }
+    return res.(i0ce6dbcacbf3c79ca963160a4abb755a6a06643231b513d9e0f9a89464cc184b.Applicationable), nil
+}
+// ToGetRequestInformation get the properties and relationships of an application object.
+func (m *ApplicationItemRequestBuilder) ToGetRequestInformation(ctx context.Context, requestConfiguration *ApplicationItemRequestBuilderGetRequestConfiguration)(*i2ae4187f7daee263371cb1c977df639813ab50ffa529013b7437480d1ec0158f.RequestInformation, error) {
+    requestInfo := i2ae4187f7daee263371cb1c977df639813ab50ffa529013b7437480d1ec0158f.NewRequestInformation()
+    requestInfo.UrlTemplate = m.urlTemplate
+    requestInfo.PathParameters = m.pathParameters
+    requestInfo.Method = i2ae4187f7daee263371cb1c977df639813ab50ffa529013b7437480d1ec0158f.GET
+    requestInfo.Headers.Add("Accept", "application/json")
+    if requestConfiguration != nil {
+        if requestConfiguration.QueryParameters != nil {
+            requestInfo.AddQueryParameters(*(requestConfiguration.QueryParameters))
+        }
+        requestInfo.Headers.AddAll(requestConfiguration.Headers)
+        requestInfo.AddRequestOptions(requestConfiguration.Options)
+    }
+    return requestInfo, nil
+}
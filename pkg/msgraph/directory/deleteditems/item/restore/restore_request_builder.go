@@ -0,0 +1,79 @@
+package restore
+
+import (
+    "context"
+    i2ae4187f7daee263371cb1c977df639813ab50ffa529013b7437480d1ec0158f "github.com/microsoft/kiota-abstractions-go"
+    i0ce6dbcacbf3c79ca963160a4abb755a6a06643231b513d9e0f9a89464cc184b "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/models"
+    i0add6a40679c106013e9da46a5d72e4fa956366e39a3307fa519ccb21a5fcf80 "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/models/odataerrors"
+)
+
+// RestoreRequestBuilder builds and executes requests for operations under \directory\deletedItems\{directoryObject-id}\microsoft.graph.restore
+type RestoreRequestBuilder struct {
+    // Path parameters for the request
+    pathParameters map[string]string
+    // The request adapter to use to execute the requests.
+    requestAdapter i2ae4187f7daee263371cb1c977df639813ab50ffa529013b7437480d1ec0158f.RequestAdapter
+    // Url template to use to build the URL for the current request builder
+    urlTemplate string
+}
+// RestoreRequestBuilderPostRequestConfiguration configuration for the request such as headers, query parameters, and middleware options.
+type RestoreRequestBuilderPostRequestConfiguration struct {
+    // Request headers
+    Headers *i2ae4187f7daee263371cb1c977df639813ab50ffa529013b7437480d1ec0158f.RequestHeaders
+    // Request options
+    Options []i2ae4187f7daee263371cb1c977df639813ab50ffa529013b7437480d1ec0158f.RequestOption
+}
+// NewRestoreRequestBuilderInternal instantiates a new RestoreRequestBuilder and sets the default values.
+func NewRestoreRequestBuilderInternal(pathParameters map[string]string, requestAdapter i2ae4187f7daee263371cb1c977df639813ab50ffa529013b7437480d1ec0158f.RequestAdapter)(*RestoreRequestBuilder) {
+    m := &RestoreRequestBuilder{
+    }
+    m.urlTemplate = "{+baseurl}/directory/deletedItems/{directoryObject%2Did}/microsoft.graph.restore";
+    urlTplParams := make(map[string]string)
+    for idx, item := range pathParameters {
+        urlTplParams[idx] = item
+    }
+    m.pathParameters = urlTplParams
+    m.requestAdapter = requestAdapter
+    return m
+}
+// NewRestoreRequestBuilder instantiates a new RestoreRequestBuilder and sets the default values.
+func NewRestoreRequestBuilder(rawUrl string, requestAdapter i2ae4187f7daee263371cb1c977df639813ab50ffa529013b7437480d1ec0158f.RequestAdapter)(*RestoreRequestBuilder) {
+    urlParams := make(map[string]string)
+    urlParams["request-raw-url"] = rawUrl
+    return NewRestoreRequestBuilderInternal(urlParams, requestAdapter)
+}
+// Post restore a recently deleted application, group, servicePrincipal, administrative unit, or user object from deleted items.
+// [Find more info here]
+// 
+// [Find more info here]: https://docs.microsoft.com/graph/api/directory-deleteditems-restore?view=graph-rest-1.0
+func (m *RestoreRequestBuilder) Post(ctx context.Context, requestConfiguration *RestoreRequestBuilderPostRequestConfiguration)(i0ce6dbcacbf3c79ca963160a4abb755a6a06643231b513d9e0f9a89464cc184b.DirectoryObjectable, error) {
+    requestInfo, err := m.ToPostRequestInformation(ctx, requestConfiguration);
+    if err != nil {
+        return nil, err
+    }
+    errorMapping := i2ae4187f7daee263371cb1c977df639813ab50ffa529013b7437480d1ec0158f.ErrorMappings {
+        "4XX": i0add6a40679c106013e9da46a5d72e4fa956366e39a3307fa519ccb21a5fcf80.CreateODataErrorFromDiscriminatorValue,
+        "5XX": i0add6a40679c106013e9da46a5d72e4fa956366e39a3307fa519ccb21a5fcf80.CreateODataErrorFromDiscriminatorValue,
+    }
+    res, err := m.requestAdapter.Send(ctx, requestInfo, i0ce6dbcacbf3c79ca963160a4abb755a6a06643231b513d9e0f9a89464cc184b.CreateDirectoryObjectFromDiscriminatorValue, errorMapping)
+    if err != nil {
+        return nil, err
+    }
+    if res == nil {
+        return nil, nil
+    }
+    return res.(i0ce6dbcacbf3c79ca963160a4abb755a6a06643231b513d9e0f9a89464cc184b.DirectoryObjectable), nil
+}
+// ToPostRequestInformation restore a recently deleted application, group, servicePrincipal, administrative unit, or user object from deleted items.
+func (m *RestoreRequestBuilder) ToPostRequestInformation(ctx context.Context, requestConfiguration *RestoreRequestBuilderPostRequestConfiguration)(*i2ae4187f7daee263371cb1c977df639813ab50ffa529013b7437480d1ec0158f.RequestInformation, error) {
+    requestInfo := i2ae4187f7daee263371cb1c977df639813ab50ffa529013b7437480d1ec0158f.NewRequestInformation()
+    requestInfo.UrlTemplate = m.urlTemplate
+    requestInfo.PathParameters = m.pathParameters
+    requestInfo.Method = i2ae4187f7daee263371cb1c977df639813ab50ffa529013b7437480d1ec0158f.POST
+    requestInfo.Headers.Add("Accept", "application/json")
+    if requestConfiguration != nil {
+        requestInfo.Headers.AddAll(requestConfiguration.Headers)
+        requestInfo.AddRequestOptions(requestConfiguration.Options)
+    }
+    return requestInfo, nil
+}
@@ -0,0 +1,46 @@
+package directory
+
+import (
+    i2ae4187f7daee263371cb1c977df639813ab50ffa529013b7437480d1ec0158f "github.com/microsoft/kiota-abstractions-go"
+    ia88dda8e508356e6d24f3b8009df0bc194f0195860b79ec001c281907a2cd3e7 "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/directory/deleteditems/item"
+)
+
+// DirectoryRequestBuilder builds and executes requests for operations under \directory
+type DirectoryRequestBuilder struct {
+    // Path parameters for the request
+    pathParameters map[string]string
+    // The request adapter to use to execute the requests.
+    requestAdapter i2ae4187f7daee263371cb1c977df639813ab50ffa529013b7437480d1ec0158f.RequestAdapter
+    // Url template to use to build the URL for the current request builder
+    urlTemplate string
+}
+// NewDirectoryRequestBuilderInternal instantiates a new DirectoryRequestBuilder and sets the default values.
+func NewDirectoryRequestBuilderInternal(pathParameters map[string]string, requestAdapter i2ae4187f7daee263371cb1c977df639813ab50ffa529013b7437480d1ec0158f.RequestAdapter)(*DirectoryRequestBuilder) {
+    m := &DirectoryRequestBuilder{
+    }
+    m.urlTemplate = "{+baseurl}/directory{?%24select,%24expand}";
+    urlTplParams := make(map[string]string)
+    for idx, item := range pathParameters {
+        urlTplParams[idx] = item
+    }
+    m.pathParameters = urlTplParams
+    m.requestAdapter = requestAdapter
+    return m
+}
+// NewDirectoryRequestBuilder instantiates a new DirectoryRequestBuilder and sets the default values.
+func NewDirectoryRequestBuilder(rawUrl string, requestAdapter i2ae4187f7daee263371cb1c977df639813ab50ffa529013b7437480d1ec0158f.RequestAdapter)(*DirectoryRequestBuilder) {
+    urlParams := make(map[string]string)
+    urlParams["request-raw-url"] = rawUrl
+    return NewDirectoryRequestBuilderInternal(urlParams, requestAdapter)
+}
+// DeletedItemsById provides operations to manage the deletedItems property of the microsoft.graph.directory entity.
+func (m *DirectoryRequestBuilder) DeletedItemsById(id string)(*ia88dda8e508356e6d24f3b8009df0bc194f0195860b79ec001c281907a2cd3e7.DirectoryObjectItemRequestBuilder) {
+    urlTplParams := make(map[string]string)
+    for idx, item := range m.pathParameters {
+        urlTplParams[idx] = item
+    }
+    if id != "" {
+        urlTplParams["directoryObject%2Did"] = id
+    }
+    return ia88dda8e508356e6d24f3b8009df0bc194f0195860b79ec001c281907a2cd3e7.NewDirectoryObjectItemRequestBuilderInternal(urlTplParams, m.requestAdapter);
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+    i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91 "github.com/microsoft/kiota-abstractions-go/serialization"
+)
+
+// Application 
+type Application struct {
+    DirectoryObject
+    // The unique identifier for the application that is assigned to an application by Azure AD. Not nullable. Read-only. Supports $filter (eq).
+    appId *string
+    // The display name for the application. Supports $filter (eq, ne, not, ge, le, in, startsWith, and eq on null values), $search, and $orderBy.
+    displayName *string
+}
+// NewApplication instantiates a new application and sets the default values.
+func NewApplication()(*Application) {
+    m := &Application{
+        DirectoryObject: *NewDirectoryObject(),
+    }
+    odataTypeValue := "#microsoft.graph.application"
+    m.SetOdataType(&odataTypeValue)
+    return m
+}
+// CreateApplicationFromDiscriminatorValue creates a new instance of the appropriate class based on discriminator value
+func CreateApplicationFromDiscriminatorValue(parseNode i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.ParseNode)(i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.Parsable, error) {
+    return NewApplication(), nil
+}
+// GetAppId gets the appId property value. The unique identifier for the application that is assigned to an application by Azure AD. Not nullable. Read-only. Supports $filter (eq).
+func (m *Application) GetAppId()(*string) {
+    return m.appId
+}
+// GetDisplayName gets the displayName property value. The display name for the application. Supports $filter (eq, ne, not, ge, le, in, startsWith, and eq on null values), $search, and $orderBy.
+func (m *Application) GetDisplayName()(*string) {
+    return m.displayName
+}
+// GetFieldDeserializers the deserialization information for the current model
+func (m *Application) GetFieldDeserializers()(map[string]func(i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.ParseNode)(error)) {
+    res := m.DirectoryObject.GetFieldDeserializers()
+    res["appId"] = func (n i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.ParseNode) error {
+        val, err := n.GetStringValue()
+        if err != nil {
+            return err
+        }
+        if val != nil {
+            m.SetAppId(val)
+        }
+        return nil
+    }
+    res["displayName"] = func (n i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.ParseNode) error {
+        val, err := n.GetStringValue()
+        if err != nil {
+            return err
+        }
+        if val != nil {
+            m.SetDisplayName(val)
+        }
+        return nil
+    }
+    return res
+}
+// Serialize serializes information the current object
+func (m *Application) Serialize(writer i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.SerializationWriter)(error) {
+    err := m.DirectoryObject.Serialize(writer)
+    if err != nil {
+        return err
+    }
+    {
+        err = writer.WriteStringValue("appId", m.GetAppId())
+        if err != nil {
+            return err
+        }
+    }
+    {
+        err = writer.WriteStringValue("displayName", m.GetDisplayName())
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+// SetAppId sets the appId property value. The unique identifier for the application that is assigned to an application by Azure AD. Not nullable. Read-only. Supports $filter (eq).
+func (m *Application) SetAppId(value *string)() {
+    m.appId = value
+}
+// SetDisplayName sets the displayName property value. The display name for the application. Supports $filter (eq, ne, not, ge, le, in, startsWith, and eq on null values), $search, and $orderBy.
+func (m *Application) SetDisplayName(value *string)() {
+    m.displayName = value
+}
+// Applicationable 
+type Applicationable interface {
+    DirectoryObjectable
+    i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.Parsable
+    GetAppId()(*string)
+    GetDisplayName()(*string)
+    SetAppId(value *string)()
+    SetDisplayName(value *string)()
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+    i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91 "github.com/microsoft/kiota-abstractions-go/serialization"
+)
+
+// ApplicationCollectionResponse 
+type ApplicationCollectionResponse struct {
+    BaseCollectionPaginationCountResponse
+    // The value property
+    value []Applicationable
+}
+// NewApplicationCollectionResponse instantiates a new ApplicationCollectionResponse and sets the default values.
+func NewApplicationCollectionResponse()(*ApplicationCollectionResponse) {
+    m := &ApplicationCollectionResponse{
+        BaseCollectionPaginationCountResponse: *NewBaseCollectionPaginationCountResponse(),
+    }
+    return m
+}
+// CreateApplicationCollectionResponseFromDiscriminatorValue creates a new instance of the appropriate class based on discriminator value
+func CreateApplicationCollectionResponseFromDiscriminatorValue(parseNode i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.ParseNode)(i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.Parsable, error) {
+    return NewApplicationCollectionResponse(), nil
+}
+// GetFieldDeserializers the deserialization information for the current model
+func (m *ApplicationCollectionResponse) GetFieldDeserializers()(map[string]func(i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.ParseNode)(error)) {
+    res := m.BaseCollectionPaginationCountResponse.GetFieldDeserializers()
+    res["value"] = func (n i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.ParseNode) error {
+        val, err := n.GetCollectionOfObjectValues(CreateApplicationFromDiscriminatorValue)
+        if err != nil {
+            return err
+        }
+        if val != nil {
+            res := make([]Applicationable, len(val))
+            for i, v := range val {
+                res[i] = v.(Applicationable)
+            }
+            m.SetValue(res)
+        }
+        return nil
+    }
+    return res
+}
+// GetValue gets the value property value. The value property
+func (m *ApplicationCollectionResponse) GetValue()([]Applicationable) {
+    return m.value
+}
+// Serialize serializes information the current object
+func (m *ApplicationCollectionResponse) Serialize(writer i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.SerializationWriter)(error) {
+    err := m.BaseCollectionPaginationCountResponse.Serialize(writer)
+    if err != nil {
+        return err
+    }
+    if m.GetValue() != nil {
+        cast := make([]i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.Parsable, len(m.GetValue()))
+        for i, v := range m.GetValue() {
+            cast[i] = v.(i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.Parsable)
+        }
+        err = writer.WriteCollectionOfObjectValues("value", cast)
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+// SetValue sets the value property value. The value property
+func (m *ApplicationCollectionResponse) SetValue(value []Applicationable)() {
+    m.value = value
+}
+// ApplicationCollectionResponseable 
+type ApplicationCollectionResponseable interface {
+    BaseCollectionPaginationCountResponseable
+    i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.Parsable
+    GetValue()([]Applicationable)
+    SetValue(value []Applicationable)()
+}
@@ -34,12 +34,18 @@ func CreateDirectoryObjectFromDiscriminatorValue(parseNode i878a80d2330e89d26896
                 switch *mappingValue {
                     case "#microsoft.graph.appRoleAssignment":
                         return NewAppRoleAssignment(), nil
+                    case "#microsoft.graph.application":
+                        return NewApplication(), nil
                     case "#microsoft.graph.device":
                         return NewDevice(), nil
+                    case "#microsoft.graph.extensionProperty":
+                        return NewExtensionProperty(), nil
                     case "#microsoft.graph.group":
                         return NewGroup(), nil
                     case "#microsoft.graph.resourceSpecificPermissionGrant":
                         return NewResourceSpecificPermissionGrant(), nil
+                    case "#microsoft.graph.servicePrincipal":
+                        return NewServicePrincipal(), nil
                     case "#microsoft.graph.user":
                         return NewUser(), nil
                 }
@@ -0,0 +1,313 @@
+package models
+
+import (
+    i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91 "github.com/microsoft/kiota-abstractions-go/serialization"
+)
+
+// ServicePrincipal 
+type ServicePrincipal struct {
+    DirectoryObject
+    // true if the service principal account is enabled; otherwise, false. Supports $filter (eq, ne, not, in).
+    accountEnabled *bool
+    // The display name exposed by the associated application.
+    appDisplayName *string
+    // The unique identifier for the associated application (its appId property). Supports $filter (eq, ne, not, in, startsWith).
+    appId *string
+    // App role assignments for this app or service, granted to users, groups, and other service principals. Supports $expand.
+    appRoleAssignedTo []AppRoleAssignmentable
+    // Specifies whether users or other service principals need to be granted an app role assignment for this service principal before users can sign in or apps can get tokens. The default value is false. Not nullable. Supports $filter (eq, ne, NOT).
+    appRoleAssignmentRequired *bool
+    // The roles exposed by the application which this service principal represents. For more information see the appRoles property definition on the application entity. Not nullable.
+    appRoles []AppRoleable
+    // The display name for the service principal. Supports $filter (eq, ne, NOT, ge, le, in, startsWith), $search, and $orderBy.
+    displayName *string
+    // Identifies whether the service principal represents an application, a managed identity, or a legacy application. This is set by Azure AD internally. The servicePrincipalType property can be set to three different values: __Application__ - A service principal that represents an application or service. The appId property identifies the associated app registration, and matches the appId of an application, possibly from a different tenant. If the associated app registration is missing, tokens are not issued for the service principal.__ManagedIdentity__ - A service principal that represents a managed identity. Service principals representing managed identities can be granted access and permissions, but cannot be updated or modified directly.__Legacy__ - A service principal that represents an app created before app registrations, or through legacy experiences. Legacy service principal can have credentials, service principal names, reply URLs, and other properties which are editable by an authorized user, but does not have an associated app registration. The appId value does not associate the service principal with an app registration. The service principal can only be used in the tenant where it was created.
+    servicePrincipalType *string
+    // Custom strings that can be used to categorize and identify the service principal. Not nullable. Supports $filter (eq, NOT, ge, le, startsWith).
+    tags []string
+}
+// NewServicePrincipal instantiates a new servicePrincipal and sets the default values.
+func NewServicePrincipal()(*ServicePrincipal) {
+    m := &ServicePrincipal{
+        DirectoryObject: *NewDirectoryObject(),
+    }
+    odataTypeValue := "#microsoft.graph.servicePrincipal"
+    m.SetOdataType(&odataTypeValue)
+    return m
+}
+// CreateServicePrincipalFromDiscriminatorValue creates a new instance of the appropriate class based on discriminator value
+func CreateServicePrincipalFromDiscriminatorValue(parseNode i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.ParseNode)(i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.Parsable, error) {
+    return NewServicePrincipal(), nil
+}
+// GetAccountEnabled gets the accountEnabled property value. true if the service principal account is enabled; otherwise, false. Supports $filter (eq, ne, not, in).
+func (m *ServicePrincipal) GetAccountEnabled()(*bool) {
+    return m.accountEnabled
+}
+// GetAppDisplayName gets the appDisplayName property value. The display name exposed by the associated application.
+func (m *ServicePrincipal) GetAppDisplayName()(*string) {
+    return m.appDisplayName
+}
+// GetAppId gets the appId property value. The unique identifier for the associated application (its appId property). Supports $filter (eq, ne, not, in, startsWith).
+func (m *ServicePrincipal) GetAppId()(*string) {
+    return m.appId
+}
+// GetAppRoleAssignedTo gets the appRoleAssignedTo property value. App role assignments for this app or service, granted to users, groups, and other service principals. Supports $expand.
+func (m *ServicePrincipal) GetAppRoleAssignedTo()([]AppRoleAssignmentable) {
+    return m.appRoleAssignedTo
+}
+// GetAppRoleAssignmentRequired gets the appRoleAssignmentRequired property value. Specifies whether users or other service principals need to be granted an app role assignment for this service principal before users can sign in or apps can get tokens. The default value is false. Not nullable. Supports $filter (eq, ne, NOT).
+func (m *ServicePrincipal) GetAppRoleAssignmentRequired()(*bool) {
+    return m.appRoleAssignmentRequired
+}
+// GetAppRoles gets the appRoles property value. The roles exposed by the application which this service principal represents. For more information see the appRoles property definition on the application entity. Not nullable.
+func (m *ServicePrincipal) GetAppRoles()([]AppRoleable) {
+    return m.appRoles
+}
+// GetDisplayName gets the displayName property value. The display name for the service principal. Supports $filter (eq, ne, NOT, ge, le, in, startsWith), $search, and $orderBy.
+func (m *ServicePrincipal) GetDisplayName()(*string) {
+    return m.displayName
+}
+// GetFieldDeserializers the deserialization information for the current model
+func (m *ServicePrincipal) GetFieldDeserializers()(map[string]func(i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.ParseNode)(error)) {
+    res := m.DirectoryObject.GetFieldDeserializers()
+    res["accountEnabled"] = func (n i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.ParseNode) error {
+        val, err := n.GetBoolValue()
+        if err != nil {
+            return err
+        }
+        if val != nil {
+            m.SetAccountEnabled(val)
+        }
+        return nil
+    }
+    res["appDisplayName"] = func (n i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.ParseNode) error {
+        val, err := n.GetStringValue()
+        if err != nil {
+            return err
+        }
+        if val != nil {
+            m.SetAppDisplayName(val)
+        }
+        return nil
+    }
+    res["appId"] = func (n i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.ParseNode) error {
+        val, err := n.GetStringValue()
+        if err != nil {
+            return err
+        }
+        if val != nil {
+            m.SetAppId(val)
+        }
+        return nil
+    }
+    res["appRoleAssignedTo"] = func (n i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.ParseNode) error {
+        val, err := n.GetCollectionOfObjectValues(CreateAppRoleAssignmentFromDiscriminatorValue)
+        if err != nil {
+            return err
+        }
+        if val != nil {
+            res := make([]AppRoleAssignmentable, len(val))
+            for i, v := range val {
+                res[i] = v.(AppRoleAssignmentable)
+            }
+            m.SetAppRoleAssignedTo(res)
+        }
+        return nil
+    }
+    res["appRoleAssignmentRequired"] = func (n i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.ParseNode) error {
+        val, err := n.GetBoolValue()
+        if err != nil {
+            return err
+        }
+        if val != nil {
+            m.SetAppRoleAssignmentRequired(val)
+        }
+        return nil
+    }
+    res["appRoles"] = func (n i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.ParseNode) error {
+        val, err := n.GetCollectionOfObjectValues(CreateAppRoleFromDiscriminatorValue)
+        if err != nil {
+            return err
+        }
+        if val != nil {
+            res := make([]AppRoleable, len(val))
+            for i, v := range val {
+                res[i] = v.(AppRoleable)
+            }
+            m.SetAppRoles(res)
+        }
+        return nil
+    }
+    res["displayName"] = func (n i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.ParseNode) error {
+        val, err := n.GetStringValue()
+        if err != nil {
+            return err
+        }
+        if val != nil {
+            m.SetDisplayName(val)
+        }
+        return nil
+    }
+    res["servicePrincipalType"] = func (n i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.ParseNode) error {
+        val, err := n.GetStringValue()
+        if err != nil {
+            return err
+        }
+        if val != nil {
+            m.SetServicePrincipalType(val)
+        }
+        return nil
+    }
+    res["tags"] = func (n i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.ParseNode) error {
+        val, err := n.GetCollectionOfPrimitiveValues("string")
+        if err != nil {
+            return err
+        }
+        if val != nil {
+            res := make([]string, len(val))
+            for i, v := range val {
+                res[i] = *(v.(*string))
+            }
+            m.SetTags(res)
+        }
+        return nil
+    }
+    return res
+}
+// GetServicePrincipalType gets the servicePrincipalType property value. Identifies whether the service principal represents an application, a managed identity, or a legacy application. This is set by Azure AD internally. The servicePrincipalType property can be set to three different values: __Application__ - A service principal that represents an application or service. The appId property identifies the associated app registration, and matches the appId of an application, possibly from a different tenant. If the associated app registration is missing, tokens are not issued for the service principal.__ManagedIdentity__ - A service principal that represents a managed identity. Service principals representing managed identities can be granted access and permissions, but cannot be updated or modified directly.__Legacy__ - A service principal that represents an app created before app registrations, or through legacy experiences. Legacy service principal can have credentials, service principal names, reply URLs, and other properties which are editable by an authorized user, but does not have an associated app registration. The appId value does not associate the service principal with an app registration. The service principal can only be used in the tenant where it was created.
+func (m *ServicePrincipal) GetServicePrincipalType()(*string) {
+    return m.servicePrincipalType
+}
+// GetTags gets the tags property value. Custom strings that can be used to categorize and identify the service principal. Not nullable. Supports $filter (eq, NOT, ge, le, startsWith).
+func (m *ServicePrincipal) GetTags()([]string) {
+    return m.tags
+}
+// Serialize serializes information the current object
+func (m *ServicePrincipal) Serialize(writer i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.SerializationWriter)(error) {
+    err := m.DirectoryObject.Serialize(writer)
+    if err != nil {
+        return err
+    }
+    {
+        err = writer.WriteBoolValue("accountEnabled", m.GetAccountEnabled())
+        if err != nil {
+            return err
+        }
+    }
+    {
+        err = writer.WriteStringValue("appDisplayName", m.GetAppDisplayName())
+        if err != nil {
+            return err
+        }
+    }
+    {
+        err = writer.WriteStringValue("appId", m.GetAppId())
+        if err != nil {
+            return err
+        }
+    }
+    if m.GetAppRoleAssignedTo() != nil {
+        cast := make([]i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.Parsable, len(m.GetAppRoleAssignedTo()))
+        for i, v := range m.GetAppRoleAssignedTo() {
+            cast[i] = v.(i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.Parsable)
+        }
+        err = writer.WriteCollectionOfObjectValues("appRoleAssignedTo", cast)
+        if err != nil {
+            return err
+        }
+    }
+    {
+        err = writer.WriteBoolValue("appRoleAssignmentRequired", m.GetAppRoleAssignmentRequired())
+        if err != nil {
+            return err
+        }
+    }
+    if m.GetAppRoles() != nil {
+        cast := make([]i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.Parsable, len(m.GetAppRoles()))
+        for i, v := range m.GetAppRoles() {
+            cast[i] = v.(i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.Parsable)
+        }
+        err = writer.WriteCollectionOfObjectValues("appRoles", cast)
+        if err != nil {
+            return err
+        }
+    }
+    {
+        err = writer.WriteStringValue("displayName", m.GetDisplayName())
+        if err != nil {
+            return err
+        }
+    }
+    {
+        err = writer.WriteStringValue("servicePrincipalType", m.GetServicePrincipalType())
+        if err != nil {
+            return err
+        }
+    }
+    if m.GetTags() != nil {
+        err = writer.WriteCollectionOfStringValues("tags", m.GetTags())
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+// SetAccountEnabled sets the accountEnabled property value. true if the service principal account is enabled; otherwise, false. Supports $filter (eq, ne, not, in).
+func (m *ServicePrincipal) SetAccountEnabled(value *bool)() {
+    m.accountEnabled = value
+}
+// SetAppDisplayName sets the appDisplayName property value. The display name exposed by the associated application.
+func (m *ServicePrincipal) SetAppDisplayName(value *string)() {
+    m.appDisplayName = value
+}
+// SetAppId sets the appId property value. The unique identifier for the associated application (its appId property). Supports $filter (eq, ne, not, in, startsWith).
+func (m *ServicePrincipal) SetAppId(value *string)() {
+    m.appId = value
+}
+// SetAppRoleAssignedTo sets the appRoleAssignedTo property value. App role assignments for this app or service, granted to users, groups, and other service principals. Supports $expand.
+func (m *ServicePrincipal) SetAppRoleAssignedTo(value []AppRoleAssignmentable)() {
+    m.appRoleAssignedTo = value
+}
+// SetAppRoleAssignmentRequired sets the appRoleAssignmentRequired property value. Specifies whether users or other service principals need to be granted an app role assignment for this service principal before users can sign in or apps can get tokens. The default value is false. Not nullable. Supports $filter (eq, ne, NOT).
+func (m *ServicePrincipal) SetAppRoleAssignmentRequired(value *bool)() {
+    m.appRoleAssignmentRequired = value
+}
+// SetAppRoles sets the appRoles property value. The roles exposed by the application which this service principal represents. For more information see the appRoles property definition on the application entity. Not nullable.
+func (m *ServicePrincipal) SetAppRoles(value []AppRoleable)() {
+    m.appRoles = value
+}
+// SetDisplayName sets the displayName property value. The display name for the service principal. Supports $filter (eq, ne, NOT, ge, le, in, startsWith), $search, and $orderBy.
+func (m *ServicePrincipal) SetDisplayName(value *string)() {
+    m.displayName = value
+}
+// SetServicePrincipalType sets the servicePrincipalType property value. Identifies whether the service principal represents an application, a managed identity, or a legacy application. This is set by Azure AD internally. The servicePrincipalType property can be set to three different values: __Application__ - A service principal that represents an application or service. The appId property identifies the associated app registration, and matches the appId of an application, possibly from a different tenant. If the associated app registration is missing, tokens are not issued for the service principal.__ManagedIdentity__ - A service principal that represents a managed identity. Service principals representing managed identities can be granted access and permissions, but cannot be updated or modified directly.__Legacy__ - A service principal that represents an app created before app registrations, or through legacy experiences. Legacy service principal can have credentials, service principal names, reply URLs, and other properties which are editable by an authorized user, but does not have an associated app registration. The appId value does not associate the service principal with an app registration. The service principal can only be used in the tenant where it was created.
+func (m *ServicePrincipal) SetServicePrincipalType(value *string)() {
+    m.servicePrincipalType = value
+}
+// SetTags sets the tags property value. Custom strings that can be used to categorize and identify the service principal. Not nullable. Supports $filter (eq, NOT, ge, le, startsWith).
+func (m *ServicePrincipal) SetTags(value []string)() {
+    m.tags = value
+}
+// ServicePrincipalable 
+type ServicePrincipalable interface {
+    DirectoryObjectable
+    i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91.Parsable
+    GetAccountEnabled()(*bool)
+    GetAppDisplayName()(*string)
+    GetAppId()(*string)
+    GetAppRoleAssignedTo()([]AppRoleAssignmentable)
+    GetAppRoleAssignmentRequired()(*bool)
+    GetAppRoles()([]AppRoleable)
+    GetDisplayName()(*string)
+    GetServicePrincipalType()(*string)
+    GetTags()([]string)
+    SetAccountEnabled(value *bool)()
+    SetAppDisplayName(value *string)()
+    SetAppId(value *string)()
+    SetAppRoleAssignedTo(value []AppRoleAssignmentable)()
+    SetAppRoleAssignmentRequired(value *bool)()
+    SetAppRoles(value []AppRoleable)()
+    SetDisplayName(value *string)()
+    SetServicePrincipalType(value *string)()
+    SetTags(value []string)()
+}
@@ -7,6 +7,14 @@ import (
     i7294a22093d408fdca300f11b81a887d89c47b764af06c8b803e2323973fdb83 "github.com/microsoft/kiota-serialization-text-go"
     i878a80d2330e89d26896388a3f487eef27b0a0e6c010c493bf80be1452208f91 "github.com/microsoft/kiota-abstractions-go/serialization"
     i4c3f247974914a9e23feaf6d37c7d926f8f54bc5ee4d11b6234f59cd87fc5672 "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/users"
+    i8e7a74bb270b3a8c6f8ac699a3f4cf37eace9eec1553f77532064303a84992e9 "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/users/item"
+    i92661e0b61e6217f2683b9ad6e805ec07b0fe1dbbae9d92f667e2efbd6872b6f "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/directory"
+    i543a018643a0df86710fd5edffb22bf5ecfd1f5c416d24d97ab13ee027920bfe "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/serviceprincipals/item"
+    ib0b3d84c9373140a84b4438ea714ac1a9b7f7503cd994e8c4904e969c7f76d2b "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/groups"
+    i4a92d81f78b2c8461fdb54c138a8f599d6ff2fd4bc1df6ce797a7a64f4239b14 "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/groups/item"
+    i248c9090ae07e9107ce63bcdcdab7a0d2470da6faae4b31307b9ae80d0fa773c "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/serviceprincipals"
+    ia188e7bb5a729e8237aaa88f303cd8d51e3492304722c0f039e5b65fc6af2bfc "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/applications"
+    i8d8832f5d2351ad1c0a638d9f583eb5760f81c0c2e06e3cbb89a9e0f06d7423a "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/applications/item"
 )
 
 // Msgraph the main entry point of the SDK, exposes the configuration and the fluent API.
@@ -37,7 +45,67 @@ func NewMsgraph(requestAdapter i2ae4187f7daee263371cb1c977df639813ab50ffa529013b
     m.pathParameters["baseurl"] = m.requestAdapter.GetBaseUrl()
     return m
 }
+// Applications provides operations to manage the collection of application entities.
+func (m *Msgraph) Applications()(*ia188e7bb5a729e8237aaa88f303cd8d51e3492304722c0f039e5b65fc6af2bfc.ApplicationsRequestBuilder) {
+    return ia188e7bb5a729e8237aaa88f303cd8d51e3492304722c0f039e5b65fc6af2bfc.NewApplicationsRequestBuilderInternal(m.pathParameters, m.requestAdapter)
+}
+// ApplicationsById provides operations to manage the collection of application entities.
+func (m *Msgraph) ApplicationsById(id string)(*i8d8832f5d2351ad1c0a638d9f583eb5760f81c0c2e06e3cbb89a9e0f06d7423a.ApplicationItemRequestBuilder) {
+    urlTplParams := make(map[string]string)
+    for idx, item := range m.pathParameters {
+        urlTplParams[idx] = item
+    }
+    if id != "" {
+        urlTplParams["application%2Did"] = id
+    }
+    return i8d8832f5d2351ad1c0a638d9f583eb5760f81c0c2e06e3cbb89a9e0f06d7423a.NewApplicationItemRequestBuilderInternal(urlTplParams, m.requestAdapter);
+}
+// Directory provides operations to manage the directory singleton.
+func (m *Msgraph) Directory()(*i92661e0b61e6217f2683b9ad6e805ec07b0fe1dbbae9d92f667e2efbd6872b6f.DirectoryRequestBuilder) {
+    return i92661e0b61e6217f2683b9ad6e805ec07b0fe1dbbae9d92f667e2efbd6872b6f.NewDirectoryRequestBuilderInternal(m.pathParameters, m.requestAdapter)
+}
+// Groups provides operations to manage the collection of group entities.
+func (m *Msgraph) Groups()(*ib0b3d84c9373140a84b4438ea714ac1a9b7f7503cd994e8c4904e969c7f76d2b.GroupsRequestBuilder) {
+    return ib0b3d84c9373140a84b4438ea714ac1a9b7f7503cd994e8c4904e969c7f76d2b.NewGroupsRequestBuilderInternal(m.pathParameters, m.requestAdapter)
+}
+// GroupsById provides operations to manage the collection of group entities.
+func (m *Msgraph) GroupsById(id string)(*i4a92d81f78b2c8461fdb54c138a8f599d6ff2fd4bc1df6ce797a7a64f4239b14.GroupItemRequestBuilder) {
+    urlTplParams := make(map[string]string)
+    for idx, item := range m.pathParameters {
+        urlTplParams[idx] = item
+    }
+    if id != "" {
+        urlTplParams["group%2Did"] = id
+    }
+    return i4a92d81f78b2c8461fdb54c138a8f599d6ff2fd4bc1df6ce797a7a64f4239b14.NewGroupItemRequestBuilderInternal(urlTplParams, m.requestAdapter);
+}
+// ServicePrincipals provides operations to manage the collection of servicePrincipal entities.
+func (m *Msgraph) ServicePrincipals()(*i248c9090ae07e9107ce63bcdcdab7a0d2470da6faae4b31307b9ae80d0fa773c.ServicePrincipalsRequestBuilder) {
+    return i248c9090ae07e9107ce63bcdcdab7a0d2470da6faae4b31307b9ae80d0fa773c.NewServicePrincipalsRequestBuilderInternal(m.pathParameters, m.requestAdapter)
+}
+// ServicePrincipalsById provides operations to manage the collection of servicePrincipal entities.
+func (m *Msgraph) ServicePrincipalsById(id string)(*i543a018643a0df86710fd5edffb22bf5ecfd1f5c416d24d97ab13ee027920bfe.ServicePrincipalItemRequestBuilder) {
+    urlTplParams := make(map[string]string)
+    for idx, item := range m.pathParameters {
+        urlTplParams[idx] = item
+    }
+    if id != "" {
+        urlTplParams["servicePrincipal%2Did"] = id
+    }
+    return i543a018643a0df86710fd5edffb22bf5ecfd1f5c416d24d97ab13ee027920bfe.NewServicePrincipalItemRequestBuilderInternal(urlTplParams, m.requestAdapter);
+}
 // Users the users property
 func (m *Msgraph) Users()(*i4c3f247974914a9e23feaf6d37c7d926f8f54bc5ee4d11b6234f59cd87fc5672.UsersRequestBuilder) {
     return i4c3f247974914a9e23feaf6d37c7d926f8f54bc5ee4d11b6234f59cd87fc5672.NewUsersRequestBuilderInternal(m.pathParameters, m.requestAdapter)
 }
+// UsersById provides operations to manage the collection of user entities.
+func (m *Msgraph) UsersById(id string)(*i8e7a74bb270b3a8c6f8ac699a3f4cf37eace9eec1553f77532064303a84992e9.UserItemRequestBuilder) {
+    urlTplParams := make(map[string]string)
+    for idx, item := range m.pathParameters {
+        urlTplParams[idx] = item
+    }
+    if id != "" {
+        urlTplParams["user%2Did"] = id
+    }
+    return i8e7a74bb270b3a8c6f8ac699a3f4cf37eace9eec1553f77532064303a84992e9.NewUserItemRequestBuilderInternal(urlTplParams, m.requestAdapter);
+}
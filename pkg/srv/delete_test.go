@@ -0,0 +1,106 @@
+package srv_test
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/config"
+	"github.com/aserto-dev/idp-plugin-sdk/plugin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteRestoresUsers(t *testing.T) {
+	assert := require.New(t)
+
+	var requests []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method + " " + r.URL.Path {
+		case "POST /directory/deletedItems/1/microsoft.graph.restore":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"@odata.type":"#microsoft.graph.user","id":"1"}`)
+		case "POST /directory/deletedItems/2/microsoft.graph.restore":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"code":"Request_ResourceNotFound","message":"Resource '2' does not exist."}}`)
+		case "PATCH /users/2":
+			body, err := readBody(r)
+			assert.NoError(err)
+			assert.JSONEq(`{"@odata.type":"#microsoft.graph.user","accountEnabled":true}`, string(body))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+
+	azureADPlugin, _ := openTestPlugin(t, &config.AzureADConfig{DeleteMode: config.DeleteModeRestore}, plugin.OperationTypeDelete, handler)
+
+	assert.NoError(azureADPlugin.Delete("1"))
+	assert.NoError(azureADPlugin.Delete("2"))
+
+	stats, err := azureADPlugin.Close()
+	assert.NoError(err)
+	assert.Equal(int32(2), stats.Received)
+	assert.Equal(int32(2), stats.Updated)
+	assert.Equal(int32(0), stats.Deleted)
+	assert.Equal([]string{
+		"POST /directory/deletedItems/1/microsoft.graph.restore",
+		"POST /directory/deletedItems/2/microsoft.graph.restore",
+		"PATCH /users/2",
+	}, requests)
+}
+
+// readBody returns the body of r, decompressing the gzip request bodies the Graph client sends.
+func readBody(r *http.Request) ([]byte, error) {
+	var reader io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		reader = gz
+	}
+	return io.ReadAll(reader)
+}
+
+func TestDeletePurgeWaitsForDeletedItems(t *testing.T) {
+	assert := require.New(t)
+
+	purges := map[string]int{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/1", "/users/2":
+			w.WriteHeader(http.StatusNoContent)
+		case "/directory/deletedItems/1", "/directory/deletedItems/2":
+			purges[r.URL.Path]++
+			// user 1 shows up in the deleted items on the second attempt, user 2 never does
+			if r.URL.Path == "/directory/deletedItems/1" && purges[r.URL.Path] > 1 {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"code":"Request_ResourceNotFound","message":"Resource does not exist."}}`)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+
+	azureADPlugin, _ := openTestPlugin(t, &config.AzureADConfig{DeleteMode: config.DeleteModePurge}, plugin.OperationTypeDelete, handler)
+	azureADPlugin.SetPurgeDelays(time.Millisecond, time.Millisecond)
+	logged := captureLog(t)
+
+	assert.NoError(azureADPlugin.Delete("1"))
+	assert.NoError(azureADPlugin.Delete("2"))
+
+	stats, err := azureADPlugin.Close()
+	assert.NoError(err)
+	assert.Equal(int32(2), stats.Deleted)
+	assert.Equal(int32(0), stats.Errors)
+	assert.Equal(map[string]int{"/directory/deletedItems/1": 2, "/directory/deletedItems/2": 3}, purges)
+	assert.Contains(logged.String(), "user 2 was soft deleted but could not be purged")
+}
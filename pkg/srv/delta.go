@@ -0,0 +1,54 @@
+package srv
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/azureclient"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// deltaState is persisted between runs so that the next Read only returns users changed since the last one.
+type deltaState struct {
+	DeltaLink string `json:"deltaLink"`
+}
+
+// loadDeltaLink returns the delta link saved at path, or an empty string if no state was saved yet.
+func loadDeltaLink(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to read delta state file %s: %s", path, err.Error())
+	}
+
+	var state deltaState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return "", status.Errorf(codes.Internal, "failed to parse delta state file %s: %s", path, err.Error())
+	}
+	return state.DeltaLink, nil
+}
+
+// removeDeltaState deletes the state file at path, so that the next round enumerates every user again.
+func removeDeltaState(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return status.Errorf(codes.Internal, "failed to remove delta state file %s: %s", path, err.Error())
+	}
+	return nil
+}
+
+// saveDeltaLink atomically replaces the state file at path with the given delta link.
+func saveDeltaLink(path, deltaLink string) error {
+	data, err := json.Marshal(deltaState{DeltaLink: deltaLink})
+	if err != nil {
+		return err
+	}
+
+	if err := azureclient.WriteFileAtomic(path, data); err != nil {
+		return status.Errorf(codes.Internal, "failed to write delta state file %s: %s", path, err.Error())
+	}
+	return nil
+}
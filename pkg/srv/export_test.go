@@ -0,0 +1,25 @@
+package srv
+
+import (
+	"time"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/azureclient"
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/config"
+)
+
+// NewAzureADPluginWithClient returns a plugin sending its Graph requests through client.
+func NewAzureADPluginWithClient(client *azureclient.AzureADClient) *AzureADPlugin {
+	a := NewAzureADPlugin()
+	a.newClient = func(*config.AzureADConfig) (*azureclient.AzureADClient, error) {
+		return client, nil
+	}
+	return a
+}
+
+// SetPurgeDelays replaces the waits between the attempts to purge a soft deleted user.
+func (a *AzureADPlugin) SetPurgeDelays(delays ...time.Duration) {
+	a.purgeDelays = delays
+}
+
+// RandomPassword generates a password as the plugin does for the users it creates.
+var RandomPassword = randomPassword
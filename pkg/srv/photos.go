@@ -0,0 +1,105 @@
+package srv
+
+import (
+	"context"
+	"encoding/base64"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/azureclient"
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/config"
+	api "github.com/aserto-dev/go-grpc/aserto/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// photoExtensions maps the content types of profile photos onto the extensions of the files they are written to.
+var photoExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/bmp":  ".bmp",
+	"image/webp": ".webp",
+}
+
+// loadPictures sets the picture of users to their profile photo, downloading PhotoConcurrency photos at a time.
+// Users without a photo keep an empty picture, and so do the users whose photo failed to load: the failure is
+// recorded in the stats without failing the read of the others.
+func (a *AzureADPlugin) loadPictures(users []*api.User) {
+	if a.Config.Photos == "" {
+		return
+	}
+
+	ctx := context.Background()
+	errs := make([]error, len(users))
+	sem := make(chan struct{}, a.Config.PhotoConcurrencyOrDefault())
+	var wg sync.WaitGroup
+
+	for i, user := range users {
+		if user.Deleted {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, user *api.User) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			errs[i] = a.loadPicture(ctx, user)
+		}(i, user)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			a.stats.addError("photo", users[i].Id, err)
+		}
+	}
+}
+
+func (a *AzureADPlugin) loadPicture(ctx context.Context, user *api.User) error {
+	photo, err := a.azureClient.GetUserPhoto(ctx, user.Id, a.Config.PhotoSizeOrDefault())
+	if err != nil || photo == nil {
+		return err
+	}
+
+	switch a.Config.Photos {
+	case config.PhotosDataURI:
+		user.Picture = dataURI(photo)
+	case config.PhotosDirectory:
+		user.Picture, err = a.writePhoto(user.Id, photo)
+	}
+	return err
+}
+
+func dataURI(photo *azureclient.Photo) string {
+	return "data:" + photo.ContentType + ";base64," + base64.StdEncoding.EncodeToString(photo.Content)
+}
+
+// writePhoto writes a photo into the photo directory and returns the URL it is served from.
+func (a *AzureADPlugin) writePhoto(userID string, photo *azureclient.Photo) (string, error) {
+	contentType, _, _ := strings.Cut(photo.ContentType, ";")
+	ext, ok := photoExtensions[contentType]
+	if !ok {
+		ext = ".bin"
+	}
+	// object ids are GUIDs, but the name must not escape the directory whatever the id
+	name := filepath.Base(filepath.Clean("/"+userID)) + ext
+
+	path := filepath.Join(a.Config.PhotoDirectory, name)
+	if err := os.WriteFile(path, photo.Content, 0o644); err != nil { // nolint:gosec // photos are public profile data
+		return "", status.Errorf(codes.Internal, "failed to write photo of user %s: %s", userID, err.Error())
+	}
+
+	if a.Config.PhotoURLTemplate != "" {
+		return strings.ReplaceAll(a.Config.PhotoURLTemplate, config.PhotoFilePlaceholder, url.PathEscape(name)), nil
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to resolve photo of user %s: %s", userID, err.Error())
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String(), nil
+}
@@ -0,0 +1,342 @@
+package srv_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/azureclient"
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/config"
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/srv"
+	api "github.com/aserto-dev/go-grpc/aserto/api/v1"
+	"github.com/aserto-dev/idp-plugin-sdk/plugin"
+	"github.com/microsoft/kiota-abstractions-go/authentication"
+	kiotahttp "github.com/microsoft/kiota-http-go"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPlugin opens a read operation against a Graph server served by handler.
+func newTestPlugin(t *testing.T, cfg *config.AzureADConfig, handler http.Handler) (*srv.AzureADPlugin, string) {
+	return openTestPlugin(t, cfg, plugin.OperationTypeRead, handler)
+}
+
+// openTestPlugin opens an operation of type op against a Graph server served by handler.
+func openTestPlugin(t *testing.T, cfg *config.AzureADConfig, op plugin.OperationType, handler http.Handler) (*srv.AzureADPlugin, string) {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	adapter, err := kiotahttp.NewNetHttpRequestAdapter(&authentication.AnonymousAuthenticationProvider{})
+	require.NoError(t, err)
+	adapter.SetBaseUrl(server.URL)
+
+	azureADPlugin := srv.NewAzureADPluginWithClient(azureclient.NewAzureADClientWithAdapter(adapter))
+	require.NoError(t, azureADPlugin.Open(cfg, op))
+	return azureADPlugin, server.URL
+}
+
+func readAll(t *testing.T, azureADPlugin *srv.AzureADPlugin) []*api.User {
+	var users []*api.User
+	for {
+		page, err := azureADPlugin.Read()
+		if err == io.EOF {
+			return users
+		}
+		require.NoError(t, err)
+		users = append(users, page...)
+	}
+}
+
+// captureLog returns the output of the standard logger until the end of the test.
+func captureLog(t *testing.T) *bytes.Buffer {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &logged
+}
+
+func writeDeltaLink(t *testing.T, path, link string) {
+	require.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf(`{"deltaLink":%q}`, link)), 0o600))
+}
+
+func TestReadDeltaRereadsChangedUsers(t *testing.T) {
+	assert := require.New(t)
+
+	stateFile := filepath.Join(t.TempDir(), "delta.json")
+	cfg := &config.AzureADConfig{DeltaStateFile: stateFile}
+
+	var serverURL string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/microsoft.graph.delta()", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("saved", r.URL.Query().Get("$deltatoken"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"@odata.deltaLink":"%s/users/microsoft.graph.delta()?$deltatoken=next","value":[
+			{"id":"1","jobTitle":"Engineer"},
+			{"id":"2","@removed":{"reason":"changed"}},
+			{"id":"3","jobTitle":"Manager"}
+		]}`, serverURL)
+	})
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("id in ('1','3')", r.URL.Query().Get("$filter"))
+		w.Header().Set("Content-Type", "application/json")
+		// user 3 was deleted since the delta page was issued
+		fmt.Fprint(w, `{"value":[{"id":"1","displayName":"Ada","mail":"ada@contoso.com","accountEnabled":true,"userType":"Member"}]}`)
+	})
+	var azureADPlugin *srv.AzureADPlugin
+	azureADPlugin, serverURL = newTestPlugin(t, cfg, mux)
+	writeDeltaLink(t, stateFile, serverURL+"/users/microsoft.graph.delta()?$deltatoken=saved")
+
+	users := readAll(t, azureADPlugin)
+	assert.Len(users, 2)
+	assert.Equal("Ada", users[0].DisplayName)
+	assert.Equal("ada@contoso.com", users[0].Email)
+	assert.True(users[1].Deleted)
+
+	state, err := os.ReadFile(stateFile)
+	assert.NoError(err)
+	assert.Contains(string(state), "deltatoken=next")
+}
+
+func TestReadDeltaRestartsOnExpiredLink(t *testing.T) {
+	assert := require.New(t)
+
+	stateFile := filepath.Join(t.TempDir(), "delta.json")
+	cfg := &config.AzureADConfig{DeltaStateFile: stateFile}
+
+	var serverURL string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/microsoft.graph.delta()", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("$deltatoken") == "expired" {
+			w.WriteHeader(http.StatusGone)
+			fmt.Fprint(w, `{"error":{"code":"syncStateNotFound","message":"The sync state is no longer available."}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"@odata.deltaLink":"%s/users/microsoft.graph.delta()?$deltatoken=fresh","value":[
+			{"id":"1","displayName":"Ada","accountEnabled":true,"userType":"Member"}
+		]}`, serverURL)
+	})
+	var azureADPlugin *srv.AzureADPlugin
+	azureADPlugin, serverURL = newTestPlugin(t, cfg, mux)
+	writeDeltaLink(t, stateFile, serverURL+"/users/microsoft.graph.delta()?$deltatoken=expired")
+
+	users := readAll(t, azureADPlugin)
+	assert.Len(users, 1)
+	assert.Equal("Ada", users[0].DisplayName)
+
+	state, err := os.ReadFile(stateFile)
+	assert.NoError(err)
+	assert.Contains(string(state), "deltatoken=fresh")
+}
+
+func TestReadKeepsUsersWhosePhotoFailed(t *testing.T) {
+	assert := require.New(t)
+
+	cfg := &config.AzureADConfig{Photos: config.PhotosDataURI, IncludeDisabled: true, IncludeGuests: true}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"value":[
+			{"id":"1","displayName":"Ada","accountEnabled":true,"userType":"Member"},
+			{"id":"2","displayName":"Grace","accountEnabled":true,"userType":"Member"},
+			{"id":"3","displayName":"Linus","accountEnabled":true,"userType":"Member"}
+		]}`)
+	})
+	mux.HandleFunc("/users/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/2/photos/240x240/$value" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"error":{"code":"generalException","message":"An internal server error occurred."}}`)
+			return
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, _ = w.Write([]byte{0xff, 0xd8, 0xff, 0xe0})
+	})
+	azureADPlugin, _ := newTestPlugin(t, cfg, mux)
+
+	users := readAll(t, azureADPlugin)
+	assert.Len(users, 3)
+	assert.Equal("data:image/jpeg;base64,/9j/4A==", users[0].Picture)
+	assert.Empty(users[1].Picture)
+	assert.Equal("data:image/jpeg;base64,/9j/4A==", users[2].Picture)
+
+	stats, err := azureADPlugin.Close()
+	assert.NoError(err)
+	assert.Equal(int32(1), stats.Errors)
+}
+
+func TestCloseLogsStats(t *testing.T) {
+	assert := require.New(t)
+
+	logged := captureLog(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"value":[
+			{"id":"1","displayName":"Ada","accountEnabled":true,"userType":"Member"},
+			{"id":"2","displayName":"Grace","accountEnabled":true,"userType":"Member"},
+			{"id":"3","accountEnabled":true,"userType":"Member"},
+			{"id":"4","displayName":"Backup","userPrincipalName":"svc-backup@contoso.com","accountEnabled":true,"userType":"Member"}
+		]}`)
+	})
+	cfg := &config.AzureADConfig{IncludeDisabled: true, IncludeGuests: true, ServiceAccountPattern: "^svc-"}
+	azureADPlugin, _ := newTestPlugin(t, cfg, mux)
+
+	assert.Len(readAll(t, azureADPlugin), 2)
+	_, err := azureADPlugin.Close()
+	assert.NoError(err)
+	assert.Contains(logged.String(), "received 4, created 0, updated 0, deleted 0, errors 1, skipped 1, "+
+		"excluded 0 disabled, 0 guests and 1 service accounts, retried requests 0")
+}
+
+func TestReadExcludesServiceAccounts(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		// Graph v1.0 returns isResourceAccount, but always as null
+		fmt.Fprint(w, `{"@odata.context":"https://graph.microsoft.com/v1.0/$metadata#users(displayName,id,mail,userPrincipalName,accountEnabled,userType,isResourceAccount,creationType)","value":[
+			{"displayName":"Adele Vance","id":"87d349ed-44d7-43e1-9a83-5f2406dee5bd","mail":"AdeleV@contoso.onmicrosoft.com","userPrincipalName":"AdeleV@contoso.onmicrosoft.com","accountEnabled":true,"userType":"Member","isResourceAccount":null,"creationType":null},
+			{"displayName":"Backup Service","id":"5bde3e51-d13b-4db1-9948-fe4b109d11a7","mail":null,"userPrincipalName":"svc-backup@contoso.onmicrosoft.com","accountEnabled":true,"userType":"Member","isResourceAccount":null,"creationType":null},
+			{"displayName":"Service Desk","id":"4782e723-f4f4-4af3-a76e-25e3bab0d896","mail":"service.desk@contoso.onmicrosoft.com","userPrincipalName":"service.desk@contoso.onmicrosoft.com","accountEnabled":true,"userType":"Member","isResourceAccount":null,"creationType":null}
+		]}`)
+	})
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    []string
+	}{
+		{name: "no pattern", want: []string{"Adele Vance", "Backup Service", "Service Desk"}},
+		{name: "pattern", pattern: "^svc-", want: []string{"Adele Vance", "Service Desk"}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			logged := captureLog(t)
+			cfg := &config.AzureADConfig{IncludeDisabled: true, IncludeGuests: true, ServiceAccountPattern: tt.pattern}
+			azureADPlugin, _ := newTestPlugin(t, cfg, mux)
+
+			var names []string
+			for _, user := range readAll(t, azureADPlugin) {
+				names = append(names, user.DisplayName)
+			}
+			assert.Equal(tt.want, names)
+			_, err := azureADPlugin.Close()
+			assert.NoError(err)
+			assert.Contains(logged.String(), fmt.Sprintf("excluded 0 disabled, 0 guests and %d service accounts", 3-len(tt.want)))
+		})
+	}
+}
+
+func TestReadReceivedCountsExcludedUsers(t *testing.T) {
+	const (
+		spID  = "7b1d2c4e-0000-4000-8000-000000000001"
+		user1 = "7b1d2c4e-0000-4000-8000-000000000011"
+		user2 = "7b1d2c4e-0000-4000-8000-000000000012"
+		user3 = "7b1d2c4e-0000-4000-8000-000000000013"
+	)
+	users := fmt.Sprintf(`{"value":[
+		{"id":"%s","displayName":"Ada","userPrincipalName":"ada@contoso.com","accountEnabled":true,"userType":"Member"},
+		{"id":"%s","displayName":"Backup","userPrincipalName":"svc-backup@contoso.com","accountEnabled":true,"userType":"Member"},
+		{"id":"%s","displayName":"Grace","userPrincipalName":"grace@contoso.com","accountEnabled":true,"userType":"Member"}
+	]}`, user1, user2, user3)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, users)
+	})
+	mux.HandleFunc("/servicePrincipals/"+spID, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":"%s","appId":"app","displayName":"Expenses"}`, spID)
+	})
+	mux.HandleFunc("/servicePrincipals/"+spID+"/appRoleAssignedTo", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"value":[
+			{"id":"a1","principalId":"%s","principalType":"User","resourceId":"%s"},
+			{"id":"a2","principalId":"%s","principalType":"User","resourceId":"%s"},
+			{"id":"a3","principalId":"%s","principalType":"User","resourceId":"%s"}
+		]}`, user1, spID, user2, spID, user3, spID)
+	})
+
+	for name, appAssignment := range map[string]string{"paged": "", "app assignment": spID} {
+		appAssignment := appAssignment
+		t.Run(name, func(t *testing.T) {
+			assert := require.New(t)
+
+			logged := captureLog(t)
+			cfg := &config.AzureADConfig{AppAssignment: appAssignment, IncludeDisabled: true, IncludeGuests: true,
+				ServiceAccountPattern: "^svc-"}
+			azureADPlugin, _ := newTestPlugin(t, cfg, mux)
+
+			assert.Len(readAll(t, azureADPlugin), 2)
+			stats, err := azureADPlugin.Close()
+			assert.NoError(err)
+			assert.Equal(int32(3), stats.Received)
+			assert.Contains(logged.String(), "excluded 0 disabled, 0 guests and 1 service accounts")
+		})
+	}
+}
+
+func TestReadAppAssignmentsPageByPage(t *testing.T) {
+	assert := require.New(t)
+
+	const (
+		spID   = "7b1d2c4e-0000-4000-8000-000000000001"
+		roleID = "7b1d2c4e-0000-4000-8000-000000000002"
+	)
+	ids := make([]string, 16)
+	assignments := make([]string, len(ids))
+	for i := range ids {
+		ids[i] = fmt.Sprintf("7b1d2c4e-0000-4000-8000-0000000001%02d", i)
+		assignments[i] = fmt.Sprintf(`{"id":"a%d","appRoleId":"%s","principalId":"%s","principalType":"User","resourceId":"%s","resourceDisplayName":"Expenses"}`,
+			i, roleID, ids[i], spID)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/servicePrincipals/"+spID, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":"%s","appId":"app","displayName":"Expenses","appRoles":[{"id":"%s","value":"Approver","isEnabled":true}]}`, spID, roleID)
+	})
+	mux.HandleFunc("/servicePrincipals/"+spID+"/appRoleAssignedTo", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"value":[%s]}`, strings.Join(assignments, ","))
+	})
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		var users []string
+		for _, id := range ids {
+			if strings.Contains(r.URL.Query().Get("$filter"), "'"+id+"'") {
+				users = append(users, fmt.Sprintf(`{"id":"%s","displayName":"User %s","accountEnabled":true,"userType":"Member"}`, id, id))
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"value":[%s]}`, strings.Join(users, ","))
+	})
+	azureADPlugin, _ := newTestPlugin(t, &config.AzureADConfig{AppAssignment: spID}, mux)
+
+	first, err := azureADPlugin.Read()
+	assert.NoError(err)
+	assert.Len(first, 15)
+	assert.Equal(ids[0], first[0].Id)
+	assert.Equal([]string{"Approver"}, first[0].Applications["Expenses"].Roles)
+
+	second, err := azureADPlugin.Read()
+	assert.NoError(err)
+	assert.Len(second, 1)
+	assert.Equal(ids[15], second[0].Id)
+	assert.Equal([]string{"Approver"}, second[0].Applications["Expenses"].Roles)
+
+	_, err = azureADPlugin.Read()
+	assert.Equal(io.EOF, err)
+}
@@ -2,28 +2,58 @@ package srv
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"io"
+	"log"
+	"math/big"
+	"os"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/azureclient"
 	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/config"
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/models"
 	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/transform"
 	api "github.com/aserto-dev/go-grpc/aserto/api/v1"
 	"github.com/aserto-dev/idp-plugin-sdk/plugin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// purgeDelays are the waits before each new attempt to purge a user that is not yet in the deleted items,
+// because the soft delete has not reached every directory replica.
+var purgeDelays = []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second}
+
 type AzureADPlugin struct {
 	Config       *config.AzureADConfig
 	azureClient  *azureclient.AzureADClient
+	users        *azureclient.UserPageIterator
 	page         int
 	finishedRead bool
 	op           plugin.OperationType
+	stats        runStats
+	roleFilter   *regexp.Regexp
+	mapping      transform.AttributeMapping
+	inclusion    transform.Inclusion
+	options      transform.Options
+	// incremental is set when the delta round started from a saved delta link.
+	incremental bool
+	// assignments and assignedApp are set by an app assignment read.
+	assignments *azureclient.AppAssignments
+	assignedApp models.ServicePrincipalable
+	// newClient, when set, replaces the Graph client created from the config.
+	newClient func(*config.AzureADConfig) (*azureclient.AzureADClient, error)
+	// purgeDelays are the waits between the attempts to purge a soft deleted user.
+	purgeDelays []time.Duration
 }
 
 func NewAzureADPlugin() *AzureADPlugin {
 	return &AzureADPlugin{
-		Config: &config.AzureADConfig{},
+		Config:      &config.AzureADConfig{},
+		purgeDelays: purgeDelays,
 	}
 }
 
@@ -42,30 +72,85 @@ func (a *AzureADPlugin) Open(cfg plugin.Config, operation plugin.OperationType)
 	}
 
 	a.Config = azureadConfig
+	a.users = nil
 	a.page = 0
 	a.finishedRead = false
 	a.op = operation
+	a.stats = runStats{}
+	a.incremental = false
+	a.assignments = nil
+	a.assignedApp = nil
 
 	var err error
-	if azureadConfig.RefreshToken != "" {
-		a.azureClient, err = azureclient.NewAzureADClientWithRefreshToken(
-			context.Background(),
-			azureadConfig.Tenant,
-			azureadConfig.ClientID,
-			azureadConfig.ClientSecret,
-			azureadConfig.RefreshToken)
+	a.inclusion, err = azureadConfig.Inclusion()
+	if err != nil {
 		return err
 	}
 
-	a.azureClient, err = azureclient.NewAzureADClient(
-		context.Background(),
-		azureadConfig.Tenant,
-		azureadConfig.ClientID,
-		azureadConfig.ClientSecret)
-	return err
+	a.roleFilter = nil
+	if azureadConfig.GroupRoleFilter != "" {
+		a.roleFilter, err = regexp.Compile(azureadConfig.GroupRoleFilter)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid group role filter: %s", err.Error())
+		}
+	}
+
+	a.mapping, err = transform.ParseAttributeMapping(azureadConfig.AttributeMapping)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid attribute mapping: %s", err.Error())
+	}
+
+	fallbacks, err := transform.ParseDisplayNameFallbacks(azureadConfig.DisplayNameFallbacks)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	identities, err := azureadConfig.IdentitySourceList()
+	if err != nil {
+		return err
+	}
+	a.options = transform.Options{
+		DisplayNameFallbacks: fallbacks,
+		Identities:           identities,
+		RequireDisplayName:   true,
+	}
+
+	if a.newClient != nil {
+		a.azureClient, err = a.newClient(azureadConfig)
+	} else {
+		a.azureClient, err = azureadConfig.NewClient(context.Background())
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := a.addB2CAttributes(); err != nil {
+		return err
+	}
+	if err := a.addExtensions(); err != nil {
+		return err
+	}
+
+	a.azureClient.SelectUserProperties(append(a.mapping.Select(), identities.Select()...)...)
+	a.azureClient.ExpandUserProperties(a.mapping.Expand()...)
+
+	if azureadConfig.Photos == config.PhotosDirectory {
+		if err := os.MkdirAll(azureadConfig.PhotoDirectory, 0o755); err != nil { // nolint:gosec // photos are public profile data
+			return status.Errorf(codes.Internal, "failed to create photo directory %s: %s", azureadConfig.PhotoDirectory, err.Error())
+		}
+	}
+	return nil
 }
 
 func (a *AzureADPlugin) Read() ([]*api.User, error) {
+	users, err := a.read()
+	if err != nil {
+		return users, err
+	}
+	a.loadPictures(users)
+	return users, nil
+}
+
+func (a *AzureADPlugin) read() ([]*api.User, error) {
 	if a.finishedRead {
 		return nil, io.EOF
 	}
@@ -76,31 +161,277 @@ func (a *AzureADPlugin) Read() ([]*api.User, error) {
 	if a.Config.UserPID != "" {
 		user, err := a.readByPID(a.Config.UserPID)
 		if err != nil {
+			a.stats.addError("read", a.Config.UserPID, err)
 			return nil, err
 		}
+		a.stats.Received++
 		users = append(users, user)
 		return users, nil
 	}
 
 	if a.Config.UserEmail != "" {
-		return a.readByEmail(a.Config.UserEmail)
+		users, err := a.readByEmail(a.Config.UserEmail)
+		if err != nil {
+			a.stats.addError("read", a.Config.UserEmail, err)
+			return nil, err
+		}
+		return users, nil
 	}
 
-	aadUsers, err := a.azureClient.ListUsers()
+	if a.users == nil {
+		var err error
+		a.users, err = a.newUserIterator()
+		if err != nil {
+			a.stats.addError("read", "", err)
+			return nil, err
+		}
+	}
+
+	aadUsers, err := a.nextPage()
 	if err != nil {
+		a.stats.addError("read", "", err)
 		return nil, err
 	}
+	a.page++
+	a.stats.Received += int32(len(aadUsers))
 
-	for _, user := range aadUsers.GetValue() {
-		u := transform.Transform(user)
+	for _, user := range aadUsers {
+		if azureclient.IsRemoved(user) {
+			users = append(users, transform.Removed(user))
+			continue
+		}
+		if reason := a.inclusion.Excluded(user); reason != "" {
+			a.stats.excluded.add(reason, 1)
+			// a user imported by a previous round is removed once it becomes excluded
+			if a.incremental {
+				users = append(users, transform.Removed(user))
+			}
+			continue
+		}
+		u, err := a.transform(user)
+		if skippable(err) {
+			a.skip(user, err)
+			continue
+		}
+		if err != nil {
+			a.stats.addError("read", userID(user), err)
+			return nil, err
+		}
 		users = append(users, u)
 	}
 
-	a.finishedRead = true
+	if err := a.enrich(users); err != nil {
+		a.stats.addError("read", "", err)
+		return nil, err
+	}
+	a.addAssignedApp(users)
+
+	a.finishedRead = !a.users.HasNext()
+
+	if a.finishedRead && a.Config.DeltaStateFile == "" && len(a.Config.GroupRefs()) == 0 && a.Config.AppAssignment == "" {
+		if err := a.countExcluded(); err != nil {
+			a.stats.addError("read", "", err)
+			return users, err
+		}
+	}
+
+	if a.finishedRead && a.Config.DeltaStateFile != "" {
+		if err := saveDeltaLink(a.Config.DeltaStateFile, a.users.DeltaLink()); err != nil {
+			a.stats.addError("read", "", err)
+			return users, err
+		}
+	}
 
 	return users, errs
 }
 
+// addB2CAttributes maps the custom user flow attributes of a B2C tenant, looking up the b2c-extensions-app
+// they are stored on when its app id is not configured.
+func (a *AzureADPlugin) addB2CAttributes() error {
+	names := a.Config.B2CAttributeNames()
+	if !a.Config.B2C || len(names) == 0 {
+		return nil
+	}
+
+	appID := a.Config.B2CExtensionsAppID
+	if appID == "" {
+		app, err := a.azureClient.FindB2CExtensionsApp()
+		if err != nil {
+			return err
+		}
+		if app.GetAppId() == nil {
+			return status.Error(codes.Internal, "the b2c-extensions-app has no app id")
+		}
+		appID = *app.GetAppId()
+	}
+
+	extensions, err := transform.B2CExtensionMapping(appID, names)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid b2c attributes: %s", err.Error())
+	}
+	a.mapping, err = a.mapping.With(extensions)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid b2c attributes: %s", err.Error())
+	}
+	return nil
+}
+
+// addExtensions maps the configured extensions and the directory extension properties registered on
+// the extensions app. A discovered property keeps its short name unless the extension mapping names it.
+func (a *AzureADPlugin) addExtensions() error {
+	extensions, err := transform.ParseExtensionMapping(a.Config.ExtensionMapping)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid extension mapping: %s", err.Error())
+	}
+
+	if a.Config.ExtensionsApp != "" {
+		properties, err := a.azureClient.ListUserExtensionProperties(a.Config.ExtensionsApp)
+		if err != nil {
+			return err
+		}
+		names := make([]string, 0, len(properties))
+		for _, property := range properties {
+			names = append(names, *property.GetName())
+		}
+		discovered, err := transform.DirectoryExtensionMapping(names)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to map the extension properties of %s: %s", a.Config.ExtensionsApp, err.Error())
+		}
+		extensions, err = extensions.With(discovered.Excluding(a.mapping).Excluding(extensions))
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid extension mapping: %s", err.Error())
+		}
+	}
+
+	a.mapping, err = a.mapping.With(extensions)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid extension mapping: %s", err.Error())
+	}
+	return nil
+}
+
+// newUserIterator starts an enumeration of the users assigned to the configured application, of the members of
+// the configured groups, of the users matching the user filter and search, or a delta round when a delta state
+// file is configured.
+func (a *AzureADPlugin) newUserIterator() (*azureclient.UserPageIterator, error) {
+	if a.Config.AppAssignment != "" {
+		sp, err := a.azureClient.ResolveServicePrincipal(a.Config.AppAssignment)
+		if err != nil {
+			return nil, err
+		}
+		a.assignments, err = a.azureClient.ListAppAssignments(*sp.GetId())
+		if err != nil {
+			return nil, err
+		}
+		a.assignedApp = sp
+		return a.assignments.Users, nil
+	}
+
+	if refs := a.Config.GroupRefs(); len(refs) > 0 {
+		groupIDs := make([]string, 0, len(refs))
+		for _, ref := range refs {
+			group, err := a.azureClient.ResolveGroup(ref)
+			if err != nil {
+				return nil, err
+			}
+			groupIDs = append(groupIDs, *group.GetId())
+		}
+		return a.azureClient.ListGroupMembersPaged(groupIDs), nil
+	}
+
+	if a.Config.DeltaStateFile == "" {
+		return a.azureClient.ListUsersMatching(a.Config.UserQuery()), nil
+	}
+
+	deltaLink, err := loadDeltaLink(a.Config.DeltaStateFile)
+	if err != nil {
+		return nil, err
+	}
+	a.incremental = deltaLink != ""
+	return a.azureClient.ListUsersDelta(deltaLink), nil
+}
+
+// nextPage fetches the next page of users. When Graph rejects the saved delta link, the state file is removed
+// and a full delta round is started instead. The changed users of an incremental round are re-read in full.
+func (a *AzureADPlugin) nextPage() ([]models.Userable, error) {
+	ctx := context.Background()
+
+	aadUsers, err := a.users.Next(ctx)
+	if err != nil && a.incremental && a.page == 0 && azureclient.IsDeltaExpired(err) {
+		log.Printf("the delta link saved in %s was rejected, reading all users: %s",
+			a.Config.DeltaStateFile, azureclient.GraphErrorMessage(err))
+		if err := removeDeltaState(a.Config.DeltaStateFile); err != nil {
+			return nil, err
+		}
+		a.incremental = false
+		a.users = a.azureClient.ListUsersDelta("")
+		aadUsers, err = a.users.Next(ctx)
+	}
+	if err != nil || !a.incremental {
+		return aadUsers, err
+	}
+	return a.reloadChanged(aadUsers)
+}
+
+// addAssignedApp adds the app roles granted on the application of an app assignment read to its users.
+func (a *AzureADPlugin) addAssignedApp(users []*api.User) {
+	if a.assignments == nil {
+		return
+	}
+	principals := map[string]models.ServicePrincipalable{*a.assignedApp.GetId(): a.assignedApp}
+	for _, user := range users {
+		for name, app := range transform.AppRoleApplications(a.assignments.Granted[user.Id], principals) {
+			user.Applications[name] = app
+		}
+	}
+}
+
+// reloadChanged replaces the changed users of a delta page, which only carry the properties that changed,
+// with their full records. Removed users are kept as is, and users deleted since the page was issued are
+// dropped; the next round reports them as removed.
+func (a *AzureADPlugin) reloadChanged(page []models.Userable) ([]models.Userable, error) {
+	var ids []string
+	for _, user := range page {
+		if !azureclient.IsRemoved(user) && user.GetId() != nil {
+			ids = append(ids, *user.GetId())
+		}
+	}
+	if len(ids) == 0 {
+		return page, nil
+	}
+
+	full, err := a.azureClient.ListUsersByID(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]models.Userable, len(full))
+	for _, user := range full {
+		byID[userID(user)] = user
+	}
+
+	users := make([]models.Userable, 0, len(page))
+	for _, user := range page {
+		if azureclient.IsRemoved(user) {
+			users = append(users, user)
+		} else if u, ok := byID[userID(user)]; ok {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+// countExcluded adds the users left out by the Graph filters of the user query to the exclusion stats.
+func (a *AzureADPlugin) countExcluded() error {
+	for reason, query := range a.Config.ExcludedUserQueries() {
+		count, err := a.azureClient.CountUsers(query)
+		if err != nil {
+			return err
+		}
+		a.stats.excluded.add(reason, int32(count))
+	}
+	return nil
+}
+
 func (a *AzureADPlugin) readByPID(id string) (*api.User, error) {
 
 	aadUsers, err := a.azureClient.GetUserByID(id)
@@ -113,7 +444,14 @@ func (a *AzureADPlugin) readByPID(id string) (*api.User, error) {
 	if len(users) == 0 {
 		return nil, fmt.Errorf("failed to get user by pid %s", id)
 	}
-	return transform.Transform(users[0]), nil
+	user, err := a.transform(users[0])
+	if err != nil {
+		return nil, err
+	}
+	if err := a.enrich([]*api.User{user}); err != nil {
+		return nil, err
+	}
+	return user, nil
 }
 
 func (a *AzureADPlugin) readByEmail(email string) ([]*api.User, error) {
@@ -129,23 +467,339 @@ func (a *AzureADPlugin) readByEmail(email string) ([]*api.User, error) {
 	if len(azureadUsers) < 1 {
 		return nil, fmt.Errorf("failed to get user by email %s", email)
 	}
+	a.stats.Received += int32(len(azureadUsers))
 
 	for _, user := range azureadUsers {
-		apiUser := transform.Transform(user)
+		apiUser, err := a.transform(user)
+		if skippable(err) && len(azureadUsers) > 1 {
+			a.skip(user, err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
 		users = append(users, apiUser)
 	}
 
+	if err := a.enrich(users); err != nil {
+		return nil, err
+	}
 	return users, nil
 }
 
+// transform converts an AzureAD user and maps its properties. Users whose properties cannot be converted
+// fail with InvalidArgument.
+func (a *AzureADPlugin) transform(user models.Userable) (*api.User, error) {
+	apiUser, err := transform.Transform(user, a.options)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := a.mapping.Apply(user, apiUser.Attributes.Properties); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to map properties of user %s: %s", apiUser.Id, err.Error())
+	}
+
+	return apiUser, nil
+}
+
+// skippable reports whether err only concerns the user being converted, which is then skipped
+// instead of failing the whole read.
+func skippable(err error) bool {
+	return err != nil && status.Code(err) == codes.InvalidArgument
+}
+
+// skip counts a user left out of the read because it could not be converted.
+func (a *AzureADPlugin) skip(user models.Userable, err error) {
+	a.stats.skipped++
+	a.stats.addError("transform", userID(user), err)
+}
+
+func userID(user models.Userable) string {
+	if user == nil || user.GetId() == nil {
+		return ""
+	}
+	return *user.GetId()
+}
+
+// enrich adds the roles and applications that require extra Graph requests to users. The requests of all
+// the users are batched, and the service principals of their applications are cached by the client.
+func (a *AzureADPlugin) enrich(users []*api.User) error {
+	if a.Config.GroupRoles == "" && !a.Config.AppRoles {
+		return nil
+	}
+
+	ids := make([]string, 0, len(users))
+	for _, user := range users {
+		if !user.Deleted {
+			ids = append(ids, user.Id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if a.Config.GroupRoles != "" {
+		groups, err := a.azureClient.ListUsersGroups(ids, a.Config.GroupRoles == config.GroupRolesTransitive)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			if !user.Deleted {
+				user.Attributes.Roles = transform.GroupRoles(groups[user.Id], a.Config.GroupRoleProperty, a.roleFilter)
+			}
+		}
+	}
+
+	if a.Config.AppRoles {
+		assignments, err := a.azureClient.ListUsersAppRoleAssignments(ids)
+		if err != nil {
+			return err
+		}
+		var resourceIDs []string
+		for _, userAssignments := range assignments {
+			for _, assignment := range userAssignments {
+				if assignment.GetResourceId() != nil {
+					resourceIDs = append(resourceIDs, assignment.GetResourceId().String())
+				}
+			}
+		}
+		principals, err := a.azureClient.GetServicePrincipals(resourceIDs)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			if !user.Deleted {
+				user.Applications = transform.AppRoleApplications(assignments[user.Id], principals)
+			}
+		}
+	}
+
+	return nil
+}
+
 func (a *AzureADPlugin) Write(user *api.User) error {
+	a.stats.Received++
+
+	created, err := a.write(user)
+	if err != nil {
+		a.stats.addError("write", user.Id, err)
+		return err
+	}
+
+	if created {
+		a.stats.Created++
+	} else {
+		a.stats.Updated++
+	}
 	return nil
 }
 
+// write creates or updates the AzureAD user matching an Aserto user and reports whether it was created.
+func (a *AzureADPlugin) write(user *api.User) (bool, error) {
+	upn := transform.UserPrincipalName(user)
+
+	existing, err := a.azureClient.FindUser(user.Id, user.Email, upn)
+	if err != nil {
+		return false, err
+	}
+
+	aadUser := transform.ToAzureAD(user)
+
+	if existing != nil && existing.GetId() != nil {
+		return false, a.azureClient.UpdateUser(*existing.GetId(), aadUser)
+	}
+
+	if err := a.prepareCreate(aadUser); err != nil {
+		return false, err
+	}
+	if _, err := a.azureClient.CreateUser(aadUser); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// prepareCreate fills in the properties Graph requires when creating a user.
+func (a *AzureADPlugin) prepareCreate(user *models.User) error {
+	if user.GetDisplayName() == nil {
+		return status.Error(codes.InvalidArgument, "cannot create a user without a display name")
+	}
+
+	if user.GetUserPrincipalName() == nil {
+		if user.GetMail() == nil {
+			return status.Errorf(codes.InvalidArgument, "cannot create user %s without an email or username", *user.GetDisplayName())
+		}
+		upn := *user.GetMail()
+		if a.Config.UserDomain != "" {
+			upn = localPart(upn) + "@" + a.Config.UserDomain
+		}
+		user.SetUserPrincipalName(&upn)
+	}
+
+	if user.GetMailNickname() == nil {
+		nickname := mailNickname(*user.GetUserPrincipalName())
+		user.SetMailNickname(&nickname)
+	}
+
+	if user.GetAccountEnabled() == nil {
+		enabled := true
+		user.SetAccountEnabled(&enabled)
+	}
+
+	password := a.Config.InitialPassword
+	if password == "" {
+		var err error
+		password, err = randomPassword()
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to generate a password: %s", err.Error())
+		}
+	}
+	forceChange := a.Config.ForceChangePassword
+
+	profile := models.NewPasswordProfile()
+	profile.SetPassword(&password)
+	profile.SetForceChangePasswordNextSignIn(&forceChange)
+	user.SetPasswordProfile(profile)
+
+	return nil
+}
+
+func localPart(email string) string {
+	if i := strings.LastIndex(email, "@"); i >= 0 {
+		return email[:i]
+	}
+	return email
+}
+
+// mailNickname strips the characters Graph does not accept in a mailNickname.
+func mailNickname(upn string) string {
+	var b strings.Builder
+	for _, r := range localPart(upn) {
+		if r < 0x80 && !strings.ContainsRune(`@()\[]";:<>, `, r) {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return "user"
+	}
+	return b.String()
+}
+
+// passwordClasses are the character classes of a generated password, without the look-alike characters.
+var passwordClasses = []string{
+	"abcdefghijkmnopqrstuvwxyz",
+	"ABCDEFGHJKLMNPQRSTUVWXYZ",
+	"23456789",
+	"!@#$%^&*-_=+",
+}
+
+// randomPassword generates a password that satisfies the default Azure AD complexity requirements: it holds one
+// character of each class at a random position, and its other characters are drawn from all the classes.
+func randomPassword() (string, error) {
+	const length = 24
+	chars := strings.Join(passwordClasses, "")
+
+	password := make([]byte, length)
+	for i := range password {
+		class := chars
+		if i < len(passwordClasses) {
+			class = passwordClasses[i]
+		}
+		n, err := randomInt(len(class))
+		if err != nil {
+			return "", err
+		}
+		password[i] = class[n]
+	}
+
+	// Fisher-Yates shuffle, so that the guaranteed characters do not stay in front
+	for i := length - 1; i > 0; i-- {
+		j, err := randomInt(i + 1)
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j] = password[j], password[i]
+	}
+	return string(password), nil
+}
+
+// randomInt returns a uniformly distributed random number in [0, n).
+func randomInt(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(v.Int64()), nil
+}
+
 func (a *AzureADPlugin) Delete(userID string) error {
+	a.stats.Received++
+
+	if err := a.delete(userID); err != nil {
+		a.stats.addError("delete", userID, err)
+		return err
+	}
+
+	if a.Config.DeleteMode == config.DeleteModeRestore {
+		a.stats.Updated++
+	} else {
+		a.stats.Deleted++
+	}
 	return nil
 }
 
+func (a *AzureADPlugin) delete(userID string) error {
+	switch a.Config.DeleteMode {
+	case config.DeleteModeDisable:
+		return a.azureClient.SetUserEnabled(userID, false)
+	case config.DeleteModePurge:
+		if err := a.azureClient.DeleteUser(userID); err != nil {
+			return err
+		}
+		return a.purge(userID)
+	case config.DeleteModeRestore:
+		return a.restore(userID)
+	default:
+		return a.azureClient.DeleteUser(userID)
+	}
+}
+
+// purge permanently removes a user that was just soft deleted. The deleted user can take a few seconds to show up
+// in the deleted items, so the purge is attempted again while it is not found. A user that is still not found is
+// left soft deleted and logged rather than failing the delete.
+func (a *AzureADPlugin) purge(userID string) error {
+	err := a.azureClient.PurgeDeletedUser(userID)
+	for _, delay := range a.purgeDelays {
+		if status.Code(err) != codes.NotFound {
+			return err
+		}
+		time.Sleep(delay)
+		err = a.azureClient.PurgeDeletedUser(userID)
+	}
+	if status.Code(err) == codes.NotFound {
+		log.Printf("user %s was soft deleted but could not be purged: %s", userID, status.Convert(err).Message())
+		return nil
+	}
+	return err
+}
+
+// restore reverts an earlier soft delete or, when the user is not in the deleted items, an earlier disable.
+func (a *AzureADPlugin) restore(userID string) error {
+	err := a.azureClient.RestoreUser(userID)
+	if status.Code(err) == codes.NotFound {
+		return a.azureClient.SetUserEnabled(userID, true)
+	}
+	return err
+}
+
 func (a *AzureADPlugin) Close() (*plugin.Stats, error) {
-	return nil, nil
+	if a.azureClient != nil {
+		a.stats.retries = a.azureClient.Retries()
+	}
+	log.Printf("azuread plugin: %s", a.stats.summary())
+	return a.stats.snapshot(), nil
+}
+
+// ErrorDetails returns the errors counted in the stats of the current operation.
+func (a *AzureADPlugin) ErrorDetails() []ErrorDetail {
+	return a.stats.details
 }
@@ -34,7 +34,8 @@ func TestOpen(t *testing.T) {
 
 	stats, err := azureADPlugin.Close()
 	assert.Nil(err)
-	assert.Nil(stats)
+	assert.NotNil(stats)
+	assert.Equal(int32(0), stats.Received)
 }
 
 func TestWrite(t *testing.T) {
@@ -84,7 +85,9 @@ func TestReadInvalidUserID(t *testing.T) {
 
 	stats, err := azureADPlugin.Close()
 	assert.Nil(err)
-	assert.Nil(stats)
+	assert.NotNil(stats)
+	assert.Equal(int32(0), stats.Received)
+	assert.Equal(int32(1), stats.Errors)
 }
 
 func TestReadUserByID(t *testing.T) {
@@ -112,7 +115,9 @@ func TestReadUserByID(t *testing.T) {
 
 	stats, err := azureADPlugin.Close()
 	assert.Nil(err)
-	assert.Nil(stats)
+	assert.NotNil(stats)
+	assert.Equal(int32(1), stats.Received)
+	assert.Equal(int32(0), stats.Errors)
 }
 
 func TestReadInvalidUserEmail(t *testing.T) {
@@ -137,7 +142,9 @@ func TestReadInvalidUserEmail(t *testing.T) {
 
 	stats, err := azureADPlugin.Close()
 	assert.Nil(err)
-	assert.Nil(stats)
+	assert.NotNil(stats)
+	assert.Equal(int32(0), stats.Received)
+	assert.Equal(int32(1), stats.Errors)
 }
 
 func TestReadUserByEmail(t *testing.T) {
@@ -164,13 +171,19 @@ func TestReadUserByEmail(t *testing.T) {
 
 	stats, err := azureADPlugin.Close()
 	assert.Nil(err)
-	assert.Nil(stats)
+	assert.NotNil(stats)
+	assert.Equal(int32(1), stats.Received)
+	assert.Equal(int32(0), stats.Errors)
 }
 
 func TestRead(t *testing.T) {
 	assert := require.New(t)
 
 	cfg := CreateConfig()
+	// the test tenant holds 12 accounts, some of them disabled or guests, which are no longer read by default
+	cfg.IncludeDisabled = true
+	cfg.IncludeGuests = true
+	cfg.IncludeServiceAccounts = true
 	err := cfg.Validate(plugin.OperationTypeRead)
 	assert.Nil(err)
 
@@ -188,7 +201,9 @@ func TestRead(t *testing.T) {
 
 	stats, err := azureADPlugin.Close()
 	assert.Nil(err)
-	assert.Nil(stats)
+	assert.NotNil(stats)
+	assert.Equal(int32(12), stats.Received)
+	assert.Equal(int32(0), stats.Errors)
 }
 
 func TestDelete(t *testing.T) {
@@ -218,7 +233,7 @@ func TestDelete(t *testing.T) {
 
 	stats, err := azureADPlugin.Close()
 	assert.Nil(err)
-	assert.Nil(stats)
+	assert.NotNil(stats)
 
 	err = azureADPlugin.Open(&cfg, plugin.OperationTypeDelete)
 	assert.Nil(err)
@@ -228,5 +243,7 @@ func TestDelete(t *testing.T) {
 
 	stats, err = azureADPlugin.Close()
 	assert.Nil(err)
-	assert.Nil(stats)
+	assert.NotNil(stats)
+	assert.Equal(int32(1), stats.Received)
+	assert.Equal(int32(1), stats.Deleted)
 }
@@ -0,0 +1,66 @@
+package srv
+
+import (
+	"fmt"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/transform"
+	"github.com/aserto-dev/idp-plugin-sdk/plugin"
+)
+
+// ErrorDetail records a single failed operation for troubleshooting.
+type ErrorDetail struct {
+	Operation string
+	UserID    string
+	Err       error
+}
+
+// exclusionStats counts the users left out of a read, by reason.
+type exclusionStats struct {
+	Disabled        int32
+	Guests          int32
+	ServiceAccounts int32
+}
+
+func (s *exclusionStats) add(reason string, count int32) {
+	switch reason {
+	case transform.ExcludedDisabled:
+		s.Disabled += count
+	case transform.ExcludedGuest:
+		s.Guests += count
+	case transform.ExcludedServiceAccount:
+		s.ServiceAccounts += count
+	}
+}
+
+// runStats accumulates the counters returned by Close, along with the errors behind the Errors count,
+// the number of retried requests and the excluded and skipped users.
+type runStats struct {
+	plugin.Stats
+	details  []ErrorDetail
+	retries  int32
+	excluded exclusionStats
+	skipped  int32
+}
+
+func (s *runStats) addError(operation, userID string, err error) {
+	s.Errors++
+	s.details = append(s.details, ErrorDetail{
+		Operation: operation,
+		UserID:    userID,
+		Err:       err,
+	})
+}
+
+func (s *runStats) snapshot() *plugin.Stats {
+	stats := s.Stats
+	return &stats
+}
+
+// summary describes the counters of the operation on a single line. It is logged at Close, as the host discards
+// the stats Close returns.
+func (s *runStats) summary() string {
+	return fmt.Sprintf("received %d, created %d, updated %d, deleted %d, errors %d, skipped %d, "+
+		"excluded %d disabled, %d guests and %d service accounts, retried requests %d",
+		s.Received, s.Created, s.Updated, s.Deleted, s.Errors, s.skipped,
+		s.excluded.Disabled, s.excluded.Guests, s.excluded.ServiceAccounts, s.retries)
+}
@@ -0,0 +1,118 @@
+package srv_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/config"
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/srv"
+	api "github.com/aserto-dev/go-grpc/aserto/api/v1"
+	"github.com/aserto-dev/idp-plugin-sdk/plugin"
+	"github.com/stretchr/testify/require"
+)
+
+type createdUser struct {
+	UserPrincipalName string `json:"userPrincipalName"`
+	MailNickname      string `json:"mailNickname"`
+	Mail              string `json:"mail"`
+	AccountEnabled    bool   `json:"accountEnabled"`
+	PasswordProfile   struct {
+		Password                      string `json:"password"`
+		ForceChangePasswordNextSignIn bool   `json:"forceChangePasswordNextSignIn"`
+	} `json:"passwordProfile"`
+}
+
+func TestWriteCreatesUser(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         *config.AzureADConfig
+		upn         string
+		password    string
+		forceChange bool
+	}{
+		{
+			name:        "upn in the user domain and generated password",
+			cfg:         &config.AzureADConfig{UserDomain: "contoso.onmicrosoft.com", ForceChangePassword: true},
+			upn:         "jane.doe@contoso.onmicrosoft.com",
+			forceChange: true,
+		},
+		{
+			name:     "upn from the email and initial password",
+			cfg:      &config.AzureADConfig{InitialPassword: "Initial-Passw0rd"},
+			upn:      "jane.doe@contoso.com",
+			password: "Initial-Passw0rd",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			var created []createdUser
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.Method {
+				case http.MethodGet:
+					assert.Equal("/users", r.URL.Path)
+					fmt.Fprint(w, `{"value":[]}`)
+				case http.MethodPost:
+					assert.Equal("/users", r.URL.Path)
+					body, err := readBody(r)
+					assert.NoError(err)
+					var user createdUser
+					assert.NoError(json.Unmarshal(body, &user))
+					created = append(created, user)
+					w.WriteHeader(http.StatusCreated)
+					fmt.Fprint(w, `{"id":"42"}`)
+				default:
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			})
+
+			azureADPlugin, _ := openTestPlugin(t, tt.cfg, plugin.OperationTypeWrite, handler)
+			assert.NoError(azureADPlugin.Write(&api.User{
+				Id:          "2ff319e101e1",
+				DisplayName: "Jane Doe",
+				Email:       "jane.doe@contoso.com",
+			}))
+
+			stats, err := azureADPlugin.Close()
+			assert.NoError(err)
+			assert.Equal(int32(1), stats.Created)
+			assert.Len(created, 1)
+
+			user := created[0]
+			assert.Equal(tt.upn, user.UserPrincipalName)
+			assert.Equal("jane.doe", user.MailNickname)
+			assert.Equal("jane.doe@contoso.com", user.Mail)
+			assert.True(user.AccountEnabled)
+			assert.Equal(tt.forceChange, user.PasswordProfile.ForceChangePasswordNextSignIn)
+			if tt.password != "" {
+				assert.Equal(tt.password, user.PasswordProfile.Password)
+			} else {
+				assert.Len(user.PasswordProfile.Password, 24)
+			}
+		})
+	}
+}
+
+func TestRandomPassword(t *testing.T) {
+	assert := require.New(t)
+
+	classes := []string{"abcdefghijkmnopqrstuvwxyz", "ABCDEFGHJKLMNPQRSTUVWXYZ", "23456789", "!@#$%^&*-_=+"}
+	prefixes := map[string]bool{}
+	for i := 0; i < 50; i++ {
+		password, err := srv.RandomPassword()
+		assert.NoError(err)
+		assert.Len(password, 24)
+		for _, class := range classes {
+			assert.True(strings.ContainsAny(password, class), "%s has no character of %s", password, class)
+		}
+		prefixes[password[:4]] = true
+	}
+	// the guaranteed characters are shuffled in, not left at the front
+	assert.Greater(len(prefixes), 1)
+}
@@ -0,0 +1,56 @@
+package transform
+
+import (
+	"regexp"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/models"
+)
+
+// Reasons a user is excluded from a read.
+const (
+	ExcludedDisabled       = "disabled"
+	ExcludedGuest          = "guest"
+	ExcludedServiceAccount = "service-account"
+)
+
+// UserTypeMember and UserTypeGuest are the values of the Graph userType property.
+const (
+	UserTypeMember = "Member"
+	UserTypeGuest  = "Guest"
+)
+
+// Inclusion selects the accounts read besides enabled member users.
+type Inclusion struct {
+	Disabled        bool
+	Guests          bool
+	ServiceAccounts bool
+	// ServiceAccountPattern, when set, matches the user principal names of service accounts besides the
+	// resource accounts reported by Graph.
+	ServiceAccountPattern *regexp.Regexp
+}
+
+// Excluded returns the reason a user is left out of a read, or an empty string when it is kept.
+// A user matching several reasons is reported under the first of disabled, guest and service account.
+// Guests are recognized by IsGuest, from their userType or, lacking one, their creationType or UPN.
+// Properties the user does not carry, such as in a delta page, never exclude it.
+func (i Inclusion) Excluded(user models.Userable) string {
+	if !i.Disabled && user.GetAccountEnabled() != nil && !*user.GetAccountEnabled() {
+		return ExcludedDisabled
+	}
+	if !i.Guests && IsGuest(user) {
+		return ExcludedGuest
+	}
+	if !i.ServiceAccounts && i.isServiceAccount(user) {
+		return ExcludedServiceAccount
+	}
+	return ""
+}
+
+func (i Inclusion) isServiceAccount(user models.Userable) bool {
+	// resource accounts back Teams rooms and devices rather than people
+	if user.GetIsResourceAccount() != nil && *user.GetIsResourceAccount() {
+		return true
+	}
+	upn := user.GetUserPrincipalName()
+	return i.ServiceAccountPattern != nil && upn != nil && i.ServiceAccountPattern.MatchString(*upn)
+}
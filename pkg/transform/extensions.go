@@ -0,0 +1,141 @@
+package transform
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// The kinds of extensions read by ParseExtensionMapping.
+const (
+	// ExtensionDirectory is a directory extension property registered on an application, stored on users as
+	// a top-level extension_{appId}_{name} property.
+	ExtensionDirectory = "directory"
+	// ExtensionSchema is a schema extension, stored on users as a complex property named after its id.
+	ExtensionSchema = "schema"
+	// ExtensionOpen is an open extension, read from the extensions relationship of users.
+	ExtensionOpen = "open"
+)
+
+// expandExtensions is the Graph user relationship holding the open extensions.
+const expandExtensions = "extensions"
+
+// directoryExtensionPattern matches the Graph names of directory extension properties, capturing their short name.
+var directoryExtensionPattern = regexp.MustCompile(`^extension_[0-9A-Fa-f]{32}_([A-Za-z0-9_]+)$`)
+
+// ParseExtensionMapping parses a comma separated list of kind:source[:property] entries mapping extension values
+// onto user properties, for example
+// "directory:extension_b7b1c57b532f40b8b5ed4b7a7ba67401_costCenter,schema:contoso_employee/level:grade,open:com.contoso.settings/theme".
+// Directory entries name the extension property and default to its short name; schema and open entries name
+// the extension, then the property read from it after a slash, and default to that property.
+func ParseExtensionMapping(spec string) (AttributeMapping, error) {
+	var mapping AttributeMapping
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kind, rest, _ := strings.Cut(entry, ":")
+		source, name, _ := strings.Cut(rest, ":")
+		source = strings.TrimSpace(source)
+
+		var p PropertyMapping
+		switch strings.TrimSpace(kind) {
+		case ExtensionDirectory:
+			match := directoryExtensionPattern.FindStringSubmatch(source)
+			if match == nil {
+				return nil, fmt.Errorf("invalid directory extension property %q", source)
+			}
+			p = PropertyMapping{Path: []string{source}, Name: match[1]}
+
+		case ExtensionSchema:
+			id, property, ok := strings.Cut(source, "/")
+			if !ok || !segmentPattern.MatchString(id) || !segmentPattern.MatchString(property) {
+				return nil, fmt.Errorf("invalid schema extension property %q; expected extensionId/property", source)
+			}
+			p = PropertyMapping{Path: []string{id, property}, Name: property}
+
+		case ExtensionOpen:
+			extension, property, ok := strings.Cut(source, "/")
+			if !ok || extension == "" || !segmentPattern.MatchString(property) {
+				return nil, fmt.Errorf("invalid open extension property %q; expected extensionName/property", source)
+			}
+			p = PropertyMapping{Path: []string{property}, Name: property, OpenExtension: extension}
+
+		default:
+			return nil, fmt.Errorf("invalid extension kind %q in %q; expected %s, %s or %s",
+				kind, entry, ExtensionDirectory, ExtensionSchema, ExtensionOpen)
+		}
+
+		if name = strings.TrimSpace(name); name != "" {
+			p.Name = name
+		}
+		var err error
+		if mapping, err = mapping.With(AttributeMapping{p}); err != nil {
+			return nil, err
+		}
+	}
+
+	return mapping, nil
+}
+
+// DirectoryExtensionMapping maps the given directory extension properties, named extension_{appId}_{name},
+// onto properties named after their short name.
+func DirectoryExtensionMapping(properties []string) (AttributeMapping, error) {
+	mapping := make(AttributeMapping, 0, len(properties))
+	for _, property := range properties {
+		match := directoryExtensionPattern.FindStringSubmatch(property)
+		if match == nil {
+			return nil, fmt.Errorf("invalid directory extension property %q", property)
+		}
+		mapping = append(mapping, PropertyMapping{Path: []string{property}, Name: match[1]})
+	}
+	return mapping, nil
+}
+
+// Expand returns the Graph user relationships read by the mapping, as expected by $expand.
+func (m AttributeMapping) Expand() []string {
+	for _, p := range m {
+		if p.OpenExtension != "" {
+			return []string{expandExtensions}
+		}
+	}
+	return nil
+}
+
+// Excluding returns the entries of the mapping whose Graph value is not already read by other.
+func (m AttributeMapping) Excluding(other AttributeMapping) AttributeMapping {
+	read := make(map[string]bool, len(other))
+	for _, p := range other {
+		read[p.source()] = true
+	}
+	var kept AttributeMapping
+	for _, p := range m {
+		if !read[p.source()] {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+func (p PropertyMapping) source() string {
+	return p.OpenExtension + "/" + strings.Join(p.Path, ".")
+}
+
+// openExtension returns the values of the open extension of a serialized user with the given name.
+// Extension names are case-insensitive.
+func openExtension(values map[string]any, name string) map[string]any {
+	extensions, _ := values[expandExtensions].([]any)
+	for _, e := range extensions {
+		extension, ok := e.(map[string]any)
+		if !ok {
+			continue
+		}
+		if extensionName, _ := extension["extensionName"].(string); strings.EqualFold(extensionName, name) {
+			return extension
+		}
+	}
+	return nil
+}
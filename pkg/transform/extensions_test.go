@@ -0,0 +1,109 @@
+package transform_test
+
+import (
+	"testing"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/models"
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/transform"
+	jsonserialization "github.com/microsoft/kiota-serialization-json-go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const costCenterExtension = "extension_b7b1c57b532f40b8b5ed4b7a7ba67401_costCenter"
+
+func TestParseExtensionMapping(t *testing.T) {
+	assert := require.New(t)
+
+	mapping, err := transform.ParseExtensionMapping("directory:" + costCenterExtension + ", schema:contoso_employee/level:grade," +
+		"open:com.contoso.settings/theme")
+	assert.NoError(err)
+	assert.Equal(transform.AttributeMapping{
+		{Path: []string{costCenterExtension}, Name: "costCenter"},
+		{Path: []string{"contoso_employee", "level"}, Name: "grade"},
+		{Path: []string{"theme"}, Name: "theme", OpenExtension: "com.contoso.settings"},
+	}, mapping)
+	assert.Equal([]string{costCenterExtension, "contoso_employee"}, mapping.Select())
+	assert.Equal([]string{"extensions"}, mapping.Expand())
+
+	mapping, err = transform.ParseExtensionMapping("")
+	assert.NoError(err)
+	assert.Empty(mapping)
+	assert.Empty(mapping.Expand())
+
+	for _, spec := range []string{
+		"directory:costCenter",
+		"schema:contoso_employee",
+		"open:/theme",
+		"custom:contoso/level",
+		"schema:contoso_employee/level,open:com.contoso.settings/level",
+	} {
+		_, err = transform.ParseExtensionMapping(spec)
+		assert.Error(err, spec)
+	}
+}
+
+func TestExtensionMappingApply(t *testing.T) {
+	assert := require.New(t)
+
+	content := []byte(`{
+		"id": "1",
+		"displayName": "Ada",
+		"extension_b7b1c57b532f40b8b5ed4b7a7ba67401_costCenter": "CC-42",
+		"extension_b7b1c57b532f40b8b5ed4b7a7ba67401_skills": ["go", "graph"],
+		"contoso_employee": {"level": 3, "badges": ["gold"]},
+		"extensions": [
+			{"@odata.type": "#microsoft.graph.openTypeExtension", "id": "com.contoso.other", "extensionName": "com.contoso.other", "theme": "light"},
+			{"@odata.type": "#microsoft.graph.openTypeExtension", "id": "com.contoso.settings", "extensionName": "com.contoso.settings", "theme": "dark", "tags": ["a", "b"]}
+		]
+	}`)
+	node, err := jsonserialization.NewJsonParseNode(content)
+	assert.NoError(err)
+	parsed, err := node.GetObjectValue(models.CreateUserFromDiscriminatorValue)
+	assert.NoError(err)
+
+	mapping, err := transform.ParseExtensionMapping("schema:contoso_employee/level,schema:contoso_employee/badges," +
+		"open:COM.CONTOSO.SETTINGS/theme,open:com.contoso.settings/tags,open:com.contoso.missing/theme:missing")
+	assert.NoError(err)
+	discovered, err := transform.DirectoryExtensionMapping([]string{
+		costCenterExtension,
+		"extension_b7b1c57b532f40b8b5ed4b7a7ba67401_skills",
+	})
+	assert.NoError(err)
+	mapping, err = mapping.With(discovered)
+	assert.NoError(err)
+
+	props := &structpb.Struct{Fields: make(map[string]*structpb.Value)}
+	assert.NoError(mapping.Apply(parsed.(models.Userable), props))
+
+	assert.Equal("CC-42", props.Fields["costCenter"].GetStringValue())
+	assert.Equal(float64(3), props.Fields["level"].GetNumberValue())
+	assert.Equal("dark", props.Fields["theme"].GetStringValue())
+	assert.NotContains(props.Fields, "missing")
+
+	skills := props.Fields["skills"].GetListValue()
+	assert.NotNil(skills)
+	assert.Equal([]any{"go", "graph"}, skills.AsSlice())
+	assert.Equal([]any{"gold"}, props.Fields["badges"].GetListValue().AsSlice())
+	assert.Equal([]any{"a", "b"}, props.Fields["tags"].GetListValue().AsSlice())
+}
+
+func TestDirectoryExtensionMappingExcluding(t *testing.T) {
+	assert := require.New(t)
+
+	discovered, err := transform.DirectoryExtensionMapping([]string{
+		costCenterExtension,
+		"extension_b7b1c57b532f40b8b5ed4b7a7ba67401_skills",
+	})
+	assert.NoError(err)
+
+	configured, err := transform.ParseExtensionMapping("directory:" + costCenterExtension + ":cc")
+	assert.NoError(err)
+
+	assert.Equal(transform.AttributeMapping{
+		{Path: []string{"extension_b7b1c57b532f40b8b5ed4b7a7ba67401_skills"}, Name: "skills"},
+	}, discovered.Excluding(configured))
+
+	_, err = transform.DirectoryExtensionMapping([]string{"costCenter"})
+	assert.Error(err)
+}
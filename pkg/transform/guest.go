@@ -0,0 +1,84 @@
+package transform
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/models"
+)
+
+// Values of the Graph externalUserState property of B2B guests.
+const (
+	ExternalUserStatePendingAcceptance = "PendingAcceptance"
+	ExternalUserStateAccepted          = "Accepted"
+)
+
+const (
+	// CreationTypeInvitation is the creationType of the users invited as B2B guests.
+	CreationTypeInvitation = "Invitation"
+	// externalMarker separates the mangled external email from the tenant domain in the UPN of a guest.
+	externalMarker = "#EXT#"
+	// PropertyExternalUserState is the attribute property holding the invitation state of a guest.
+	PropertyExternalUserState = "externalUserState"
+)
+
+// IsGuest reports whether a Graph user is a B2B guest, from its userType or creationType,
+// or from the #EXT# marker of its UPN when neither is known.
+func IsGuest(in models.Userable) bool {
+	if userType := in.GetUserType(); userType != nil && *userType != "" {
+		return strings.EqualFold(*userType, UserTypeGuest)
+	}
+	if creationType := in.GetCreationType(); creationType != nil && strings.EqualFold(*creationType, CreationTypeInvitation) {
+		return true
+	}
+	_, ok := ExternalEmail(stringValue(in.GetUserPrincipalName()))
+	return ok
+}
+
+// ExternalEmail reconstructs the original email of a guest from its UPN, where the @ of the email is replaced
+// by an underscore and the tenant domain appended after #EXT#: jane_contoso.com#EXT#@tenant.onmicrosoft.com
+// is jane@contoso.com. It reports false for UPNs without the marker.
+func ExternalEmail(upn string) (string, bool) {
+	i := strings.Index(strings.ToUpper(upn), externalMarker)
+	if i <= 0 {
+		return "", false
+	}
+	mangled := upn[:i]
+	// the domain cannot contain an underscore, so the last one stands for the @
+	at := strings.LastIndex(mangled, "_")
+	if at <= 0 || at == len(mangled)-1 {
+		return "", false
+	}
+	return mangled[:at] + "@" + mangled[at+1:], true
+}
+
+// primaryEmail returns the email of a user: its mail, its email sign-in name, or its UPN. The UPN of a guest
+// is replaced by the external email it was built from.
+func primaryEmail(in models.Userable) string {
+	if mail := stringValue(in.GetMail()); mail != "" {
+		return mail
+	}
+	if email := signInEmail(in); email != "" {
+		return email
+	}
+	upn := stringValue(in.GetUserPrincipalName())
+	if IsGuest(in) {
+		if email, ok := ExternalEmail(upn); ok {
+			return email
+		}
+	}
+	return upn
+}
+
+// pendingGuest reports whether a guest has not redeemed its invitation yet, so that its email is not verified.
+func pendingGuest(in models.Userable) bool {
+	return strings.EqualFold(stringValue(in.GetExternalUserState()), ExternalUserStatePendingAcceptance)
+}
+
+// setGuestProperties exposes the invitation state of a guest in the attribute properties.
+func setGuestProperties(in models.Userable, props *structpb.Struct) {
+	if state := stringValue(in.GetExternalUserState()); state != "" {
+		props.Fields[PropertyExternalUserState] = structpb.NewStringValue(state)
+	}
+}
@@ -0,0 +1,94 @@
+package transform_test
+
+import (
+	"testing"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/models"
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/transform"
+	api "github.com/aserto-dev/go-grpc/aserto/api/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalEmail(t *testing.T) {
+	tests := []struct {
+		upn  string
+		want string
+		ok   bool
+	}{
+		{upn: "jane_contoso.com#EXT#@tenant.onmicrosoft.com", want: "jane@contoso.com", ok: true},
+		{upn: "jane_doe_contoso.co.uk#ext#@tenant.onmicrosoft.com", want: "jane_doe@contoso.co.uk", ok: true},
+		{upn: "jane@tenant.onmicrosoft.com"},
+		{upn: "#EXT#@tenant.onmicrosoft.com"},
+		{upn: "jane#EXT#@tenant.onmicrosoft.com"},
+		{upn: "jane_#EXT#@tenant.onmicrosoft.com"},
+		{upn: ""},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.upn, func(t *testing.T) {
+			assert := require.New(t)
+			got, ok := transform.ExternalEmail(tt.upn)
+			assert.Equal(tt.ok, ok)
+			assert.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestTransformGuest(t *testing.T) {
+	newGuest := func(userType, creationType, state *string) models.Userable {
+		id, upn := "1", "jane_contoso.com#EXT#@tenant.onmicrosoft.com"
+		user := models.NewUser()
+		user.SetId(&id)
+		user.SetUserPrincipalName(&upn)
+		user.SetUserType(userType)
+		user.SetCreationType(creationType)
+		user.SetExternalUserState(state)
+		return user
+	}
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name     string
+		user     models.Userable
+		email    string
+		verified bool
+		state    string
+	}{
+		{name: "accepted guest", user: newGuest(str("Guest"), nil, str("Accepted")), email: "jane@contoso.com", verified: true, state: "Accepted"},
+		{name: "pending guest", user: newGuest(str("Guest"), nil, str("PendingAcceptance")), email: "jane@contoso.com", verified: false, state: "PendingAcceptance"},
+		{name: "invited", user: newGuest(nil, str("Invitation"), nil), email: "jane@contoso.com", verified: true},
+		{name: "upn marker only", user: newGuest(nil, nil, nil), email: "jane@contoso.com", verified: true},
+		{name: "member keeps its upn", user: newGuest(str("Member"), nil, nil), email: "jane_contoso.com#EXT#@tenant.onmicrosoft.com", verified: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			apiUser, err := transform.Transform(tt.user, transform.Options{})
+			assert.NoError(err)
+
+			assert.Equal(tt.email, apiUser.Email)
+			assert.Equal(api.IdentityKind_IDENTITY_KIND_EMAIL, apiUser.Identities[tt.email].GetKind())
+			assert.Equal(tt.verified, apiUser.Identities[tt.email].GetVerified())
+			assert.Equal(tt.state, apiUser.Attributes.Properties.Fields[transform.PropertyExternalUserState].GetStringValue())
+		})
+	}
+}
+
+func TestTransformGuestWithMail(t *testing.T) {
+	assert := require.New(t)
+	id, mail, upn, guest := "1", "jane@contoso.com", "jane_contoso.com#EXT#@tenant.onmicrosoft.com", "Guest"
+	user := models.NewUser()
+	user.SetId(&id)
+	user.SetMail(&mail)
+	user.SetUserPrincipalName(&upn)
+	user.SetUserType(&guest)
+
+	apiUser, err := transform.Transform(user, transform.Options{DisplayNameFallbacks: []transform.DisplayNameFallback{transform.FallbackUPN}})
+	assert.NoError(err)
+	assert.Equal("jane@contoso.com", apiUser.Email)
+	assert.Equal("jane@contoso.com", apiUser.DisplayName)
+}
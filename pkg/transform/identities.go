@@ -0,0 +1,257 @@
+package transform
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+
+	api "github.com/aserto-dev/go-grpc/aserto/api/v1"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/models"
+)
+
+// Graph user properties identities can be extracted from.
+const (
+	IdentityMail                        = "mail"
+	IdentityMobilePhone                 = "mobilePhone"
+	IdentityUserPrincipalName           = "userPrincipalName"
+	IdentityProxyAddresses              = "proxyAddresses"
+	IdentityOtherMails                  = "otherMails"
+	IdentityEmployeeID                  = "employeeId"
+	IdentityOnPremisesSamAccountName    = "onPremisesSamAccountName"
+	IdentityOnPremisesUserPrincipalName = "onPremisesUserPrincipalName"
+	IdentityOnPremisesDistinguishedName = "onPremisesDistinguishedName"
+	// IdentitySignIns holds the local and federated sign-in names of Azure AD B2C users.
+	IdentitySignIns = "identities"
+)
+
+// Sign-in types of the identities of a user.
+const (
+	SignInTypeEmailAddress = "emailAddress"
+	SignInTypeUserName     = "userName"
+	SignInTypePhoneNumber  = "phoneNumber"
+	SignInTypeFederated    = "federated"
+)
+
+// identityExtractor reads the identities of one kind a Graph user property holds, or, when typed is set,
+// identities of several kinds and providers.
+type identityExtractor struct {
+	kind     api.IdentityKind
+	verified bool
+	values   func(models.Userable) []string
+	typed    func(models.Userable) []identityValue
+}
+
+type identityValue struct {
+	value    string
+	kind     api.IdentityKind
+	provider string
+}
+
+var identityExtractors = map[string]identityExtractor{
+	// mail falls back to the email sign-in name, the external email of a guest or the userPrincipalName,
+	// as the email of the user does
+	IdentityMail: {kind: api.IdentityKind_IDENTITY_KIND_EMAIL, verified: true, values: func(in models.Userable) []string {
+		return []string{primaryEmail(in)}
+	}},
+	IdentityMobilePhone: {kind: api.IdentityKind_IDENTITY_KIND_PHONE, verified: false, values: func(in models.Userable) []string {
+		return []string{stringValue(in.GetMobilePhone())}
+	}},
+	IdentityUserPrincipalName: {kind: api.IdentityKind_IDENTITY_KIND_USERNAME, verified: true, values: func(in models.Userable) []string {
+		return []string{stringValue(in.GetUserPrincipalName())}
+	}},
+	IdentityProxyAddresses: {kind: api.IdentityKind_IDENTITY_KIND_EMAIL, verified: true, values: func(in models.Userable) []string {
+		return smtpAddresses(in.GetProxyAddresses())
+	}},
+	IdentityOtherMails: {kind: api.IdentityKind_IDENTITY_KIND_EMAIL, verified: false, values: func(in models.Userable) []string {
+		return in.GetOtherMails()
+	}},
+	IdentityEmployeeID: {kind: api.IdentityKind_IDENTITY_KIND_EMPID, verified: true, values: func(in models.Userable) []string {
+		return []string{stringValue(in.GetEmployeeId())}
+	}},
+	IdentityOnPremisesSamAccountName: {kind: api.IdentityKind_IDENTITY_KIND_USERNAME, verified: true, values: func(in models.Userable) []string {
+		return []string{stringValue(in.GetOnPremisesSamAccountName())}
+	}},
+	IdentityOnPremisesUserPrincipalName: {kind: api.IdentityKind_IDENTITY_KIND_USERNAME, verified: true, values: func(in models.Userable) []string {
+		return []string{stringValue(in.GetOnPremisesUserPrincipalName())}
+	}},
+	IdentityOnPremisesDistinguishedName: {kind: api.IdentityKind_IDENTITY_KIND_DN, verified: true, values: func(in models.Userable) []string {
+		return []string{stringValue(in.GetOnPremisesDistinguishedName())}
+	}},
+	IdentitySignIns: {verified: true, typed: signInIdentities},
+}
+
+// IdentitySource extracts the identities held by a Graph user property.
+type IdentitySource struct {
+	Property string
+	Verified bool
+}
+
+// IdentitySources lists the Graph user properties extracted into api.User identities, in order of precedence.
+type IdentitySources []IdentitySource
+
+// DefaultIdentitySources are extracted when no sources are configured.
+var DefaultIdentitySources = IdentitySources{
+	{Property: IdentityMail, Verified: true},
+	{Property: IdentityMobilePhone, Verified: false},
+}
+
+// DefaultB2CIdentitySources are extracted from the users of Azure AD B2C tenants when no sources are configured.
+var DefaultB2CIdentitySources = IdentitySources{
+	{Property: IdentitySignIns, Verified: true},
+	{Property: IdentityMail, Verified: true},
+	{Property: IdentityMobilePhone, Verified: false},
+}
+
+// ParseIdentitySources parses a comma separated list of property[:verified|unverified] entries, for example
+// "mail,userPrincipalName,proxyAddresses,otherMails:unverified". Entries without a flag use the default of
+// their property; only mobilePhone and otherMails are unverified by default. An empty list returns DefaultIdentitySources.
+func ParseIdentitySources(spec string) (IdentitySources, error) {
+	var sources IdentitySources
+	seen := make(map[string]bool)
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		property, flag, hasFlag := strings.Cut(entry, ":")
+		property = strings.TrimSpace(property)
+		extractor, ok := identityExtractors[property]
+		if !ok {
+			return nil, fmt.Errorf("invalid identity source %q", property)
+		}
+		if seen[property] {
+			return nil, fmt.Errorf("identity source %q is listed more than once", property)
+		}
+		seen[property] = true
+
+		verified := extractor.verified
+		if hasFlag {
+			switch strings.TrimSpace(flag) {
+			case "verified":
+				verified = true
+			case "unverified":
+				verified = false
+			default:
+				return nil, fmt.Errorf("invalid flag %q of identity source %s; expected verified or unverified", flag, property)
+			}
+		}
+
+		sources = append(sources, IdentitySource{Property: property, Verified: verified})
+	}
+
+	if len(sources) == 0 {
+		return DefaultIdentitySources, nil
+	}
+	return sources, nil
+}
+
+// Select returns the Graph properties read by the sources, as expected by $select.
+func (s IdentitySources) Select() []string {
+	fields := make([]string, 0, len(s))
+	for _, source := range s {
+		fields = append(fields, source.Property)
+	}
+	return fields
+}
+
+// Apply adds the identities of a Graph user to identities. Values are trimmed and de-duplicated case-insensitively,
+// including against the identities already present, so that earlier sources take precedence. The mail of a guest
+// that has not accepted its invitation is never verified.
+func (s IdentitySources) Apply(in models.Userable, identities map[string]*api.IdentitySource) {
+	seen := make(map[string]bool, len(identities))
+	for key := range identities {
+		seen[strings.ToLower(key)] = true
+	}
+
+	for _, source := range s {
+		extractor, ok := identityExtractors[source.Property]
+		if !ok {
+			continue
+		}
+		verified := source.Verified
+		// the email of a guest is only verified once the invitation sent to it is redeemed
+		if source.Property == IdentityMail && pendingGuest(in) {
+			verified = false
+		}
+		for _, identity := range extractor.extract(in) {
+			value := strings.TrimSpace(identity.value)
+			if value == "" || seen[strings.ToLower(value)] {
+				continue
+			}
+			seen[strings.ToLower(value)] = true
+			identities[value] = &api.IdentitySource{
+				Kind:     identity.kind,
+				Provider: identity.provider,
+				Verified: verified,
+			}
+		}
+	}
+}
+
+func (e identityExtractor) extract(in models.Userable) []identityValue {
+	if e.typed != nil {
+		return e.typed(in)
+	}
+	values := e.values(in)
+	identities := make([]identityValue, 0, len(values))
+	for _, value := range values {
+		identities = append(identities, identityValue{value: value, kind: e.kind, provider: Provider})
+	}
+	return identities
+}
+
+// signInIdentities maps the identities of a user onto api.User identities. Local email, user name and phone
+// sign-in names keep the azuread provider; federated ones are the unique identifiers given by their issuer,
+// such as facebook.com. The userPrincipalName identity every user carries is left to the userPrincipalName source.
+func signInIdentities(in models.Userable) []identityValue {
+	var identities []identityValue
+	for _, identity := range in.GetIdentities() {
+		if identity == nil {
+			continue
+		}
+		value := identityValue{value: stringValue(identity.GetIssuerAssignedId()), provider: Provider}
+		switch stringValue(identity.GetSignInType()) {
+		case SignInTypeEmailAddress:
+			value.kind = api.IdentityKind_IDENTITY_KIND_EMAIL
+		case SignInTypeUserName:
+			value.kind = api.IdentityKind_IDENTITY_KIND_USERNAME
+		case SignInTypePhoneNumber:
+			value.kind = api.IdentityKind_IDENTITY_KIND_PHONE
+		case SignInTypeFederated:
+			value.kind = api.IdentityKind_IDENTITY_KIND_PID
+			value.provider = cmp.Or(stringValue(identity.GetIssuer()), Provider)
+		default:
+			continue
+		}
+		identities = append(identities, value)
+	}
+	return identities
+}
+
+// signInEmail returns the first local email sign-in name of a user, which B2C users often have instead of a mail.
+func signInEmail(in models.Userable) string {
+	for _, identity := range in.GetIdentities() {
+		if identity != nil && stringValue(identity.GetSignInType()) == SignInTypeEmailAddress {
+			if email := strings.TrimSpace(stringValue(identity.GetIssuerAssignedId())); email != "" {
+				return email
+			}
+		}
+	}
+	return ""
+}
+
+// smtpAddresses returns the SMTP addresses among proxyAddresses, which are prefixed with their type:
+// SMTP: for the primary address and smtp: for the aliases. X500, SIP and other addresses are skipped.
+func smtpAddresses(proxyAddresses []string) []string {
+	var addresses []string
+	for _, address := range proxyAddresses {
+		prefix, value, ok := strings.Cut(address, ":")
+		if ok && strings.EqualFold(prefix, "smtp") {
+			addresses = append(addresses, value)
+		}
+	}
+	return addresses
+}
@@ -0,0 +1,116 @@
+package transform_test
+
+import (
+	"testing"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/models"
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/transform"
+	api "github.com/aserto-dev/go-grpc/aserto/api/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIdentitySources(t *testing.T) {
+	assert := require.New(t)
+
+	sources, err := transform.ParseIdentitySources("userPrincipalName, otherMails:verified ,mobilePhone")
+	assert.NoError(err)
+	assert.Equal(transform.IdentitySources{
+		{Property: "userPrincipalName", Verified: true},
+		{Property: "otherMails", Verified: true},
+		{Property: "mobilePhone", Verified: false},
+	}, sources)
+	assert.Equal([]string{"userPrincipalName", "otherMails", "mobilePhone"}, sources.Select())
+
+	sources, err = transform.ParseIdentitySources("")
+	assert.NoError(err)
+	assert.Equal(transform.DefaultIdentitySources, sources)
+
+	for spec, want := range map[string]string{
+		"nickname":      "invalid identity source",
+		"mail,mail":     "listed more than once",
+		"mail:trusted":  "expected verified or unverified",
+		"otherMails:":   "expected verified or unverified",
+		"mail,:unknown": "invalid identity source",
+	} {
+		_, err := transform.ParseIdentitySources(spec)
+		assert.Error(err, spec)
+		assert.Contains(err.Error(), want, spec)
+	}
+}
+
+func TestTransformIdentities(t *testing.T) {
+	assert := require.New(t)
+
+	id, mail, upn, sam, employeeID := "1", "Ada@test.com", "ada@tenant.onmicrosoft.com", "ADA", "E42"
+	user := models.NewUser()
+	user.SetId(&id)
+	user.SetMail(&mail)
+	user.SetUserPrincipalName(&upn)
+	user.SetProxyAddresses([]string{"SMTP:ada@test.com", "smtp:lovelace@test.com", "X500:/o=Test/cn=ada", "SIP:ada@test.com"})
+	user.SetOtherMails([]string{"ada@home.com", " LOVELACE@test.com "})
+	user.SetOnPremisesSamAccountName(&sam)
+	user.SetEmployeeId(&employeeID)
+
+	sources, err := transform.ParseIdentitySources("mail,userPrincipalName,proxyAddresses,otherMails,onPremisesSamAccountName,employeeId:unverified,onPremisesDistinguishedName")
+	assert.NoError(err)
+
+	apiUser, err := transform.Transform(user, transform.Options{Identities: sources})
+	assert.NoError(err)
+
+	kinds := make(map[string]api.IdentityKind)
+	verified := make(map[string]bool)
+	for key, identity := range apiUser.Identities {
+		kinds[key] = identity.Kind
+		verified[key] = identity.Verified
+	}
+	assert.Equal(map[string]api.IdentityKind{
+		"1":                          api.IdentityKind_IDENTITY_KIND_PID,
+		"Ada@test.com":               api.IdentityKind_IDENTITY_KIND_EMAIL,
+		"ada@tenant.onmicrosoft.com": api.IdentityKind_IDENTITY_KIND_USERNAME,
+		"lovelace@test.com":          api.IdentityKind_IDENTITY_KIND_EMAIL,
+		"ada@home.com":               api.IdentityKind_IDENTITY_KIND_EMAIL,
+		"ADA":                        api.IdentityKind_IDENTITY_KIND_USERNAME,
+		"E42":                        api.IdentityKind_IDENTITY_KIND_EMPID,
+	}, kinds)
+	assert.True(verified["lovelace@test.com"])
+	assert.False(verified["ada@home.com"])
+	assert.False(verified["E42"])
+}
+
+func TestTransformB2CIdentities(t *testing.T) {
+	assert := require.New(t)
+
+	newIdentity := func(signInType, issuer, issuerAssignedID string) models.ObjectIdentityable {
+		identity := models.NewObjectIdentity()
+		identity.SetSignInType(&signInType)
+		identity.SetIssuer(&issuer)
+		identity.SetIssuerAssignedId(&issuerAssignedID)
+		return identity
+	}
+
+	id, upn := "1", "cpim_5f0b3c1e@contoso.onmicrosoft.com"
+	user := models.NewUser()
+	user.SetId(&id)
+	user.SetUserPrincipalName(&upn)
+	user.SetIdentities([]models.ObjectIdentityable{
+		newIdentity("emailAddress", "contoso.onmicrosoft.com", "ada@test.com"),
+		newIdentity("userName", "contoso.onmicrosoft.com", "ada"),
+		newIdentity("phoneNumber", "contoso.onmicrosoft.com", "+40722332233"),
+		newIdentity("federated", "facebook.com", "5eecb0cd"),
+		newIdentity("userPrincipalName", "contoso.onmicrosoft.com", upn),
+		nil,
+	})
+
+	apiUser, err := transform.Transform(user, transform.Options{Identities: transform.DefaultB2CIdentitySources})
+	assert.NoError(err)
+
+	assert.Equal("ada@test.com", apiUser.Email)
+	assert.Len(apiUser.Identities, 5)
+	assert.Equal(api.IdentityKind_IDENTITY_KIND_EMAIL, apiUser.Identities["ada@test.com"].Kind)
+	assert.Equal(api.IdentityKind_IDENTITY_KIND_USERNAME, apiUser.Identities["ada"].Kind)
+	assert.Equal(api.IdentityKind_IDENTITY_KIND_PHONE, apiUser.Identities["+40722332233"].Kind)
+	assert.Equal(api.IdentityKind_IDENTITY_KIND_PID, apiUser.Identities["5eecb0cd"].Kind)
+	assert.Equal("facebook.com", apiUser.Identities["5eecb0cd"].Provider)
+	assert.Equal(transform.Provider, apiUser.Identities["ada"].Provider)
+	assert.NotContains(apiUser.Identities, upn)
+}
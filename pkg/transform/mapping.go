@@ -0,0 +1,183 @@
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	jsonserialization "github.com/microsoft/kiota-serialization-json-go"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/models"
+)
+
+var segmentPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// PropertyMapping copies the Graph user property found at Path into the api.User property Name.
+type PropertyMapping struct {
+	Path []string
+	Name string
+	// OpenExtension names the open extension of the user Path is read from; Path is then relative to it.
+	OpenExtension string
+}
+
+// AttributeMapping lists the Graph user properties copied into api.User attribute properties.
+type AttributeMapping []PropertyMapping
+
+// ParseAttributeMapping parses a comma separated list of graphPath[:property] entries, for example
+// "department,jobTitle:title,onPremisesExtensionAttributes.extensionAttribute1:costCenter".
+// Nested Graph properties are separated by dots. Entries without a property name use the last path segment.
+func ParseAttributeMapping(spec string) (AttributeMapping, error) {
+	var mapping AttributeMapping
+	names := make(map[string]bool)
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		path, name, _ := strings.Cut(entry, ":")
+		segments := strings.Split(strings.TrimSpace(path), ".")
+		for _, segment := range segments {
+			if !segmentPattern.MatchString(segment) {
+				return nil, fmt.Errorf("invalid graph property path %q", path)
+			}
+		}
+
+		name = strings.TrimSpace(name)
+		if name == "" {
+			name = segments[len(segments)-1]
+		}
+		if names[name] {
+			return nil, fmt.Errorf("property %q is mapped more than once", name)
+		}
+		names[name] = true
+
+		mapping = append(mapping, PropertyMapping{Path: segments, Name: name})
+	}
+
+	return mapping, nil
+}
+
+// B2CExtensionMapping maps the custom user flow attributes of an Azure AD B2C tenant onto properties named after them.
+// Graph stores them as extension_{appId}_{name} properties of the b2c-extensions-app, whose app id is written
+// without its dashes.
+func B2CExtensionMapping(appID string, names []string) (AttributeMapping, error) {
+	id, err := uuid.Parse(appID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid b2c-extensions-app id %q", appID)
+	}
+	prefix := "extension_" + strings.ReplaceAll(id.String(), "-", "") + "_"
+
+	mapping := make(AttributeMapping, 0, len(names))
+	for _, name := range names {
+		if !segmentPattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid attribute name %q", name)
+		}
+		mapping = append(mapping, PropertyMapping{Path: []string{prefix + name}, Name: name})
+	}
+	return mapping, nil
+}
+
+// With returns the mapping extended with other, failing when both map onto the same property.
+func (m AttributeMapping) With(other AttributeMapping) (AttributeMapping, error) {
+	names := make(map[string]bool, len(m))
+	for _, p := range m {
+		names[p.Name] = true
+	}
+	merged := append(AttributeMapping{}, m...)
+	for _, p := range other {
+		if names[p.Name] {
+			return nil, fmt.Errorf("property %q is mapped more than once", p.Name)
+		}
+		names[p.Name] = true
+		merged = append(merged, p)
+	}
+	return merged, nil
+}
+
+// Select returns the top-level Graph properties read by the mapping, as expected by $select.
+// Open extensions are not selected but expanded.
+func (m AttributeMapping) Select() []string {
+	var fields []string
+	seen := make(map[string]bool)
+	for _, p := range m {
+		if p.OpenExtension == "" && !seen[p.Path[0]] {
+			seen[p.Path[0]] = true
+			fields = append(fields, p.Path[0])
+		}
+	}
+	return fields
+}
+
+// Apply copies the mapped properties of a Graph user into props. Properties the user does not carry are skipped.
+// Strings, bools, numbers and string arrays keep their type; dates are RFC 3339 strings, and multi-valued
+// properties become lists.
+func (m AttributeMapping) Apply(in models.Userable, props *structpb.Struct) error {
+	if len(m) == 0 {
+		return nil
+	}
+
+	values, err := userValues(in)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range m {
+		source := values
+		if p.OpenExtension != "" {
+			if source = openExtension(values, p.OpenExtension); source == nil {
+				continue
+			}
+		}
+		value, ok := lookup(source, p.Path)
+		if !ok || value == nil {
+			continue
+		}
+		v, err := structpb.NewValue(value)
+		if err != nil {
+			return fmt.Errorf("failed to convert property %s: %w", strings.Join(p.Path, "."), err)
+		}
+		props.Fields[p.Name] = v
+	}
+
+	return nil
+}
+
+// userValues returns the JSON representation of a Graph user, as sent by the Graph API.
+func userValues(in models.Userable) (map[string]any, error) {
+	writer := jsonserialization.NewJsonSerializationWriter()
+	defer writer.Close()
+
+	if err := writer.WriteObjectValue("", in); err != nil {
+		return nil, fmt.Errorf("failed to serialize user: %w", err)
+	}
+	content, err := writer.GetSerializedContent()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize user: %w", err)
+	}
+
+	values := make(map[string]any)
+	if err := json.Unmarshal(content, &values); err != nil {
+		return nil, fmt.Errorf("failed to read serialized user: %w", err)
+	}
+	return values, nil
+}
+
+func lookup(values map[string]any, path []string) (any, bool) {
+	var current any = values
+	for _, segment := range path {
+		object, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		current, ok = object[segment]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
@@ -0,0 +1,106 @@
+package transform_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/models"
+	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/transform"
+	jsonserialization "github.com/microsoft/kiota-serialization-json-go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestParseAttributeMapping(t *testing.T) {
+	assert := require.New(t)
+
+	mapping, err := transform.ParseAttributeMapping("department, jobTitle:title,onPremisesExtensionAttributes.extensionAttribute1:costCenter")
+	assert.NoError(err)
+	assert.Equal(transform.AttributeMapping{
+		{Path: []string{"department"}, Name: "department"},
+		{Path: []string{"jobTitle"}, Name: "title"},
+		{Path: []string{"onPremisesExtensionAttributes", "extensionAttribute1"}, Name: "costCenter"},
+	}, mapping)
+	assert.Equal([]string{"department", "jobTitle", "onPremisesExtensionAttributes"}, mapping.Select())
+
+	mapping, err = transform.ParseAttributeMapping("")
+	assert.NoError(err)
+	assert.Empty(mapping)
+
+	_, err = transform.ParseAttributeMapping("manager..id")
+	assert.Error(err)
+
+	_, err = transform.ParseAttributeMapping("department,companyName:department")
+	assert.Error(err)
+}
+
+func TestAttributeMappingApply(t *testing.T) {
+	assert := require.New(t)
+
+	user := models.NewUser()
+	department := "Engineering"
+	user.SetDepartment(&department)
+	enabled := true
+	user.SetAccountEnabled(&enabled)
+	created := time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC)
+	user.SetCreatedDateTime(&created)
+	user.SetBusinessPhones([]string{"+1 555 0100", "+1 555 0101"})
+	extensions := models.NewOnPremisesExtensionAttributes()
+	costCenter := "CC-42"
+	extensions.SetExtensionAttribute1(&costCenter)
+	user.SetOnPremisesExtensionAttributes(extensions)
+
+	mapping, err := transform.ParseAttributeMapping("department,accountEnabled:enabled,createdDateTime:created," +
+		"businessPhones:phones,onPremisesExtensionAttributes.extensionAttribute1:costCenter,jobTitle")
+	assert.NoError(err)
+
+	props := &structpb.Struct{Fields: make(map[string]*structpb.Value)}
+	assert.NoError(mapping.Apply(user, props))
+
+	assert.Equal("Engineering", props.Fields["department"].GetStringValue())
+	assert.True(props.Fields["enabled"].GetBoolValue())
+	assert.Equal("2022-03-04T05:06:07Z", props.Fields["created"].GetStringValue())
+	assert.Len(props.Fields["phones"].GetListValue().GetValues(), 2)
+	assert.Equal("CC-42", props.Fields["costCenter"].GetStringValue())
+	assert.NotContains(props.Fields, "jobTitle")
+}
+
+func TestB2CExtensionMapping(t *testing.T) {
+	assert := require.New(t)
+
+	content := []byte(`{
+		"id": "1",
+		"displayName": "Ada",
+		"extension_5c4b3a291e8d4f6ab1c2d3e4f5a6b7c8_LoyaltyNumber": "L-42",
+		"extension_5c4b3a291e8d4f6ab1c2d3e4f5a6b7c8_Newsletter": true
+	}`)
+	node, err := jsonserialization.NewJsonParseNode(content)
+	assert.NoError(err)
+	parsed, err := node.GetObjectValue(models.CreateUserFromDiscriminatorValue)
+	assert.NoError(err)
+
+	mapping, err := transform.B2CExtensionMapping("5c4b3a29-1e8d-4f6a-b1c2-d3e4f5a6b7c8", []string{"LoyaltyNumber", "Newsletter", "Tier"})
+	assert.NoError(err)
+	assert.Equal([]string{
+		"extension_5c4b3a291e8d4f6ab1c2d3e4f5a6b7c8_LoyaltyNumber",
+		"extension_5c4b3a291e8d4f6ab1c2d3e4f5a6b7c8_Newsletter",
+		"extension_5c4b3a291e8d4f6ab1c2d3e4f5a6b7c8_Tier",
+	}, mapping.Select())
+
+	props := &structpb.Struct{Fields: make(map[string]*structpb.Value)}
+	assert.NoError(mapping.Apply(parsed.(models.Userable), props))
+	assert.Equal("L-42", props.Fields["LoyaltyNumber"].GetStringValue())
+	assert.True(props.Fields["Newsletter"].GetBoolValue())
+	assert.NotContains(props.Fields, "Tier")
+
+	_, err = transform.B2CExtensionMapping("b2c-extensions-app", []string{"LoyaltyNumber"})
+	assert.Error(err)
+	_, err = transform.B2CExtensionMapping("5c4b3a29-1e8d-4f6a-b1c2-d3e4f5a6b7c8", []string{"Loyalty Number"})
+	assert.Error(err)
+
+	attributes, err := transform.ParseAttributeMapping("department:LoyaltyNumber")
+	assert.NoError(err)
+	_, err = attributes.With(mapping)
+	assert.Error(err)
+	assert.Contains(err.Error(), "mapped more than once")
+}
@@ -1,6 +1,12 @@
 package transform
 
 import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
 	api "github.com/aserto-dev/go-grpc/aserto/api/v1"
 	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -12,69 +18,352 @@ const (
 	Provider = "azuread"
 )
 
+// Group properties that can be used to name the roles granted by group memberships.
+const (
+	GroupPropertyDisplayName  = "displayName"
+	GroupPropertyID           = "id"
+	GroupPropertyMailNickname = "mailNickname"
+)
+
+// propertySetters maps api.User attribute properties onto the Graph user fields they populate.
+var propertySetters = map[string]func(*models.User, *string){
+	"givenName":         (*models.User).SetGivenName,
+	"surname":           (*models.User).SetSurname,
+	"jobTitle":          (*models.User).SetJobTitle,
+	"department":        (*models.User).SetDepartment,
+	"companyName":       (*models.User).SetCompanyName,
+	"officeLocation":    (*models.User).SetOfficeLocation,
+	"employeeId":        (*models.User).SetEmployeeId,
+	"employeeType":      (*models.User).SetEmployeeType,
+	"city":              (*models.User).SetCity,
+	"state":             (*models.User).SetState,
+	"country":           (*models.User).SetCountry,
+	"streetAddress":     (*models.User).SetStreetAddress,
+	"postalCode":        (*models.User).SetPostalCode,
+	"usageLocation":     (*models.User).SetUsageLocation,
+	"preferredLanguage": (*models.User).SetPreferredLanguage,
+	"mailNickname":      (*models.User).SetMailNickname,
+}
+
+// ToAzureAD transforms an Aserto Edge User object definition into an AzureAD user definition.
 func ToAzureAD(in *api.User) *models.User {
 
 	user := models.NewUser()
-	user.SetId(&in.Id)
-	user.SetDisplayName(&in.DisplayName)
-	user.SetMail(&in.Email)
+	if in.DisplayName != "" {
+		user.SetDisplayName(&in.DisplayName)
+	}
+	if in.Email != "" {
+		user.SetMail(&in.Email)
+	}
+	if in.Enabled != nil {
+		user.SetAccountEnabled(in.Enabled)
+	}
+
+	var otherMails []string
+	for _, key := range sortedKeys(in.Identities) {
+		key := key
+		identity := in.Identities[key]
+		switch identity.GetKind() {
+		case api.IdentityKind_IDENTITY_KIND_EMAIL:
+			if !strings.EqualFold(key, in.Email) {
+				otherMails = append(otherMails, key)
+			}
+		case api.IdentityKind_IDENTITY_KIND_PHONE:
+			if user.GetMobilePhone() == nil {
+				user.SetMobilePhone(&key)
+			}
+		case api.IdentityKind_IDENTITY_KIND_USERNAME:
+			if user.GetUserPrincipalName() == nil && strings.Contains(key, "@") {
+				user.SetUserPrincipalName(&key)
+			}
+		case api.IdentityKind_IDENTITY_KIND_EMPID:
+			if user.GetEmployeeId() == nil {
+				user.SetEmployeeId(&key)
+			}
+		}
+	}
+	if len(otherMails) > 0 {
+		user.SetOtherMails(otherMails)
+	}
+
+	for name, value := range in.GetAttributes().GetProperties().GetFields() {
+		setter, ok := propertySetters[name]
+		if !ok {
+			continue
+		}
+		if s, ok := value.GetKind().(*structpb.Value_StringValue); ok && s.StringValue != "" {
+			v := s.StringValue
+			setter(user, &v)
+		}
+	}
 
 	return user
 }
 
+// UserPrincipalName returns the username identity of an Aserto user, if it has one.
+func UserPrincipalName(in *api.User) string {
+	for _, key := range sortedKeys(in.Identities) {
+		if in.Identities[key].GetKind() == api.IdentityKind_IDENTITY_KIND_USERNAME && strings.Contains(key, "@") {
+			return key
+		}
+	}
+	return ""
+}
+
+func sortedKeys(identities map[string]*api.IdentitySource) []string {
+	keys := make([]string, 0, len(identities))
+	for key := range identities {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// DisplayNameFallback names a source of display name for the users without a displayName.
+type DisplayNameFallback string
+
+const (
+	// FallbackName joins the given name and surname of the user.
+	FallbackName DisplayNameFallback = "name"
+	// FallbackUPN uses the userPrincipalName of the user.
+	FallbackUPN DisplayNameFallback = "upn"
+	// FallbackMail uses the mail of the user.
+	FallbackMail DisplayNameFallback = "mail"
+)
+
+// DefaultDisplayNameFallbacks are tried when no fallbacks are configured.
+var DefaultDisplayNameFallbacks = []DisplayNameFallback{FallbackName, FallbackUPN, FallbackMail}
+
+// ParseDisplayNameFallbacks parses a comma separated list of display name fallbacks, such as "name,upn".
+// An empty list returns DefaultDisplayNameFallbacks.
+func ParseDisplayNameFallbacks(spec string) ([]DisplayNameFallback, error) {
+	var fallbacks []DisplayNameFallback
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		switch fallback := DisplayNameFallback(entry); fallback {
+		case FallbackName, FallbackUPN, FallbackMail:
+			fallbacks = append(fallbacks, fallback)
+		default:
+			return nil, fmt.Errorf("invalid display name fallback %q; expected %s, %s or %s", entry, FallbackName, FallbackUPN, FallbackMail)
+		}
+	}
+	if len(fallbacks) == 0 {
+		return DefaultDisplayNameFallbacks, nil
+	}
+	return fallbacks, nil
+}
+
+// Options configures Transform.
+type Options struct {
+	// DisplayNameFallbacks are tried in order for the users without a displayName.
+	DisplayNameFallbacks []DisplayNameFallback
+	// Identities are extracted into the identities of the user, besides its object id; nil uses DefaultIdentitySources.
+	Identities IdentitySources
+	// RequireDisplayName rejects the users left without a display name by the fallbacks.
+	RequireDisplayName bool
+}
+
+// FieldError reports a user property Transform cannot convert.
+type FieldError struct {
+	UserID string
+	Field  string
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	if e.UserID == "" {
+		return fmt.Sprintf("invalid user: %s %s", e.Field, e.Reason)
+	}
+	return fmt.Sprintf("invalid user %s: %s %s", e.UserID, e.Field, e.Reason)
+}
+
 // Transform AzureAD user definition into Aserto Edge User object definition.
-func Transform(in models.Userable) *api.User {
+// It returns a *FieldError when a required property is missing or invalid.
+func Transform(in models.Userable, options Options) (*api.User, error) {
+	if in == nil {
+		return nil, &FieldError{Field: "user", Reason: "is missing"}
+	}
+	id := stringValue(in.GetId())
+	if strings.TrimSpace(id) == "" {
+		return nil, &FieldError{Field: "id", Reason: "is missing"}
+	}
 
 	user := api.User{
-		Id:          *in.GetId(),
-		DisplayName: *in.GetDisplayName(),
-		//Picture:     *in.GetPhoto().GetId(),
-		Identities: make(map[string]*api.IdentitySource),
+		Id:          id,
+		DisplayName: displayName(in, options.DisplayNameFallbacks),
+		Identities:  make(map[string]*api.IdentitySource),
 		Attributes: &api.AttrSet{
 			Properties:  &structpb.Struct{Fields: make(map[string]*structpb.Value)},
 			Roles:       []string{},
 			Permissions: []string{},
 		},
 		Applications: make(map[string]*api.AttrSet),
-		Metadata: &api.Metadata{
-			CreatedAt: timestamppb.New(*in.GetCreatedDateTime()),
-			UpdatedAt: timestamppb.New(*in.GetCreatedDateTime()),
-		},
+		Metadata:     &api.Metadata{},
 	}
 
-	email := in.GetMail()
-	if email == nil {
-		email = in.GetUserPrincipalName()
+	if user.DisplayName == "" && options.RequireDisplayName {
+		return nil, &FieldError{UserID: id, Field: "displayName", Reason: "is missing"}
 	}
 
-	if email != nil {
-		user.Email = *email
-	} else {
-		user.Email = ""
+	// delta query responses may only carry the properties that changed
+	if created := in.GetCreatedDateTime(); created != nil {
+		createdAt := timestamppb.New(*created)
+		if err := createdAt.CheckValid(); err != nil {
+			return nil, &FieldError{UserID: id, Field: "createdDateTime", Reason: err.Error()}
+		}
+		user.Metadata.CreatedAt = createdAt
+		user.Metadata.UpdatedAt = timestamppb.New(*created)
 	}
 
-	user.Identities[*in.GetId()] = &api.IdentitySource{
+	user.Email = primaryEmail(in)
+	setGuestProperties(in, user.Attributes.Properties)
+
+	user.Identities[id] = &api.IdentitySource{
 		Kind:     api.IdentityKind_IDENTITY_KIND_PID,
 		Provider: Provider,
 		Verified: true,
 	}
 
-	if email != nil && *email != "" {
-		user.Identities[*email] = &api.IdentitySource{
-			Kind:     api.IdentityKind_IDENTITY_KIND_EMAIL,
-			Provider: Provider,
-			Verified: true,
+	identities := options.Identities
+	if identities == nil {
+		identities = DefaultIdentitySources
+	}
+	identities.Apply(in, user.Identities)
+
+	return &user, nil
+}
+
+func displayName(in models.Userable, fallbacks []DisplayNameFallback) string {
+	if name := strings.TrimSpace(stringValue(in.GetDisplayName())); name != "" {
+		return name
+	}
+	for _, fallback := range fallbacks {
+		var name string
+		switch fallback {
+		case FallbackName:
+			name = strings.TrimSpace(stringValue(in.GetGivenName()) + " " + stringValue(in.GetSurname()))
+		case FallbackUPN:
+			name = strings.TrimSpace(stringValue(in.GetUserPrincipalName()))
+			if email, ok := ExternalEmail(name); ok && IsGuest(in) {
+				name = email
+			}
+		case FallbackMail:
+			name = strings.TrimSpace(stringValue(in.GetMail()))
+		}
+		if name != "" {
+			return name
 		}
 	}
+	return ""
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// Removed builds the Aserto Edge User for a user a delta query reports as removed from AzureAD.
+func Removed(in models.Userable) *api.User {
+	id := stringValue(in.GetId())
+	return &api.User{
+		Id:      id,
+		Deleted: true,
+		Identities: map[string]*api.IdentitySource{
+			id: {
+				Kind:     api.IdentityKind_IDENTITY_KIND_PID,
+				Provider: Provider,
+				Verified: true,
+			},
+		},
+	}
+}
 
-	phone := in.GetMobilePhone()
-	if phone != nil && *phone != "" {
-		user.Identities[*phone] = &api.IdentitySource{
-			Kind:     api.IdentityKind_IDENTITY_KIND_PHONE,
-			Provider: Provider,
-			Verified: false,
+// GroupRoles returns the sorted, de-duplicated role names of a user's groups, taken from the given
+// group property. When filter is set, only the names it matches are kept.
+func GroupRoles(groups []models.Groupable, property string, filter *regexp.Regexp) []string {
+	seen := make(map[string]bool)
+	roles := []string{}
+
+	for _, group := range groups {
+		var name *string
+		switch property {
+		case GroupPropertyID:
+			name = group.GetId()
+		case GroupPropertyMailNickname:
+			name = group.GetMailNickname()
+		default:
+			name = group.GetDisplayName()
 		}
+
+		if name == nil || *name == "" || seen[*name] {
+			continue
+		}
+		if filter != nil && !filter.MatchString(*name) {
+			continue
+		}
+		seen[*name] = true
+		roles = append(roles, *name)
+	}
+
+	sort.Strings(roles)
+	return roles
+}
+
+// AppRoleApplications groups a user's app role assignments by application. Each application is keyed by the
+// resource display name, or the app id of its service principal, and lists the values of the roles assigned on it.
+// principals maps resource service principal ids to the service principals exposing the roles.
+func AppRoleApplications(assignments []models.AppRoleAssignmentable, principals map[string]models.ServicePrincipalable) map[string]*api.AttrSet {
+	apps := make(map[string]*api.AttrSet)
+
+	for _, assignment := range assignments {
+		if assignment.GetResourceId() == nil {
+			continue
+		}
+		resourceID := assignment.GetResourceId().String()
+		principal := principals[resourceID]
+
+		name := resourceID
+		if assignment.GetResourceDisplayName() != nil && *assignment.GetResourceDisplayName() != "" {
+			name = *assignment.GetResourceDisplayName()
+		} else if principal != nil && principal.GetAppId() != nil {
+			name = *principal.GetAppId()
+		}
+
+		app, ok := apps[name]
+		if !ok {
+			app = &api.AttrSet{
+				Properties:  &structpb.Struct{Fields: make(map[string]*structpb.Value)},
+				Roles:       []string{},
+				Permissions: []string{},
+			}
+			apps[name] = app
+		}
+
+		// assignments to the default role (an all-zero appRoleId) grant access without a role value
+		if value := appRoleValue(principal, assignment); value != "" && !slices.Contains(app.Roles, value) {
+			app.Roles = append(app.Roles, value)
+		}
+	}
+
+	for _, app := range apps {
+		sort.Strings(app.Roles)
 	}
+	return apps
+}
 
-	return &user
+func appRoleValue(principal models.ServicePrincipalable, assignment models.AppRoleAssignmentable) string {
+	if principal == nil || assignment.GetAppRoleId() == nil {
+		return ""
+	}
+	for _, role := range principal.GetAppRoles() {
+		if role.GetId() != nil && *role.GetId() == *assignment.GetAppRoleId() && role.GetValue() != nil {
+			return *role.GetValue()
+		}
+	}
+	return ""
 }
@@ -2,12 +2,17 @@ package transform_test
 
 import (
 	"reflect"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/msgraph/models"
 	azureADTestUtils "github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/testutils"
 	"github.com/aserto-dev/aserto-idp-plugin-azuread/pkg/transform"
+	api "github.com/aserto-dev/go-grpc/aserto/api/v1"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 func TestTransformToAzureAD(t *testing.T) {
@@ -25,9 +30,270 @@ func TestTransform(t *testing.T) {
 	assert := require.New(t)
 	azureadUser := azureADTestUtils.CreateTestAzureADUser("1", "Name", "email", "pic", "+40722332233", "userName")
 
-	apiUser := *transform.Transform(azureadUser)
+	apiUser, err := transform.Transform(azureadUser, transform.Options{})
 
+	assert.NoError(err)
 	assert.Equal("1", apiUser.Id, "should correctly populate the id")
 	assert.Equal("Name", apiUser.DisplayName, "should correctly detect the displayname")
 	assert.Equal("email", apiUser.Email, "should correctly populate the email")
 }
+
+func TestTransformValidation(t *testing.T) {
+	str := func(s string) *string { return &s }
+	newUser := func(id, displayName, givenName, surname, upn *string) models.Userable {
+		user := models.NewUser()
+		user.SetId(id)
+		user.SetDisplayName(displayName)
+		user.SetGivenName(givenName)
+		user.SetSurname(surname)
+		user.SetUserPrincipalName(upn)
+		return user
+	}
+	required := transform.Options{DisplayNameFallbacks: transform.DefaultDisplayNameFallbacks, RequireDisplayName: true}
+
+	tests := []struct {
+		name        string
+		user        models.Userable
+		options     transform.Options
+		displayName string
+		err         string
+	}{
+		{name: "nil user", user: nil, err: "user is missing"},
+		{name: "no id", user: newUser(nil, str("Name"), nil, nil, nil), err: "id is missing"},
+		{name: "blank id", user: newUser(str(" "), str("Name"), nil, nil, nil), err: "id is missing"},
+		{name: "display name", user: newUser(str("1"), str("Name"), str("Given"), nil, nil), options: required, displayName: "Name"},
+		{name: "given name and surname", user: newUser(str("1"), nil, str("Ada"), str("Lovelace"), str("ada@test.com")), options: required, displayName: "Ada Lovelace"},
+		{name: "surname only", user: newUser(str("1"), str(""), nil, str("Lovelace"), nil), options: required, displayName: "Lovelace"},
+		{name: "upn", user: newUser(str("1"), nil, nil, nil, str("ada@test.com")), options: required, displayName: "ada@test.com"},
+		{
+			name:    "upn before name",
+			user:    newUser(str("1"), nil, str("Ada"), nil, str("ada@test.com")),
+			options: transform.Options{DisplayNameFallbacks: []transform.DisplayNameFallback{transform.FallbackUPN, transform.FallbackName}},
+			// the configured order wins
+			displayName: "ada@test.com",
+		},
+		{name: "missing display name", user: newUser(str("1"), nil, nil, nil, nil), options: required, err: "user 1: displayName is missing"},
+		{name: "missing display name allowed", user: newUser(str("1"), nil, nil, nil, nil), displayName: ""},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			apiUser, err := transform.Transform(tt.user, tt.options)
+			if tt.err != "" {
+				assert.Error(err)
+				assert.Contains(err.Error(), tt.err)
+				var fieldErr *transform.FieldError
+				assert.ErrorAs(err, &fieldErr)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tt.displayName, apiUser.DisplayName)
+		})
+	}
+}
+
+func TestParseDisplayNameFallbacks(t *testing.T) {
+	assert := require.New(t)
+
+	fallbacks, err := transform.ParseDisplayNameFallbacks(" upn , name ")
+	assert.NoError(err)
+	assert.Equal([]transform.DisplayNameFallback{transform.FallbackUPN, transform.FallbackName}, fallbacks)
+
+	fallbacks, err = transform.ParseDisplayNameFallbacks("")
+	assert.NoError(err)
+	assert.Equal(transform.DefaultDisplayNameFallbacks, fallbacks)
+
+	_, err = transform.ParseDisplayNameFallbacks("name,nickname")
+	assert.Error(err)
+	assert.Contains(err.Error(), "invalid display name fallback")
+}
+
+// FuzzTransform checks that Transform never panics, whatever properties a Graph user carries.
+func FuzzTransform(f *testing.F) {
+	f.Add(uint8(0xff), "1", "Name", "name@test.com", "name@tenant.com", "+40722332233", "Given", "Surname", int64(1672531200), int64(0))
+	f.Add(uint8(0), "", "", "", "", "", "", "", int64(0), int64(0))
+	f.Add(uint8(0x81), "1", "", "", "", "", "", "", int64(-62135596801), int64(0))
+	f.Add(uint8(0x81), "1", "", "", "", "", "", "", int64(253402300800), int64(999999999))
+
+	f.Fuzz(func(t *testing.T, set uint8, id, displayName, mail, upn, phone, givenName, surname string, seconds, nanos int64) {
+		user := models.NewUser()
+		setters := []func(){
+			func() { user.SetId(&id) },
+			func() { user.SetDisplayName(&displayName) },
+			func() { user.SetMail(&mail) },
+			func() { user.SetUserPrincipalName(&upn) },
+			func() { user.SetMobilePhone(&phone) },
+			func() { user.SetGivenName(&givenName) },
+			func() { user.SetSurname(&surname) },
+			func() {
+				created := time.Unix(seconds, nanos)
+				user.SetCreatedDateTime(&created)
+			},
+		}
+		for i, setter := range setters {
+			if set&(1<<i) != 0 {
+				setter()
+			}
+		}
+
+		for _, options := range []transform.Options{{}, {DisplayNameFallbacks: transform.DefaultDisplayNameFallbacks, RequireDisplayName: true}} {
+			apiUser, err := transform.Transform(user, options)
+			if err != nil {
+				var fieldErr *transform.FieldError
+				require.ErrorAs(t, err, &fieldErr)
+				continue
+			}
+			require.NotEmpty(t, apiUser.Id)
+			require.Equal(t, api.IdentityKind_IDENTITY_KIND_PID, apiUser.Identities[apiUser.Id].Kind)
+		}
+	})
+}
+
+func TestTransformToAzureADIdentitiesAndProperties(t *testing.T) {
+	assert := require.New(t)
+	apiUser := azureADTestUtils.CreateTestAPIUser("1", "Name", "email@test.com", "pic")
+	enabled := false
+	apiUser.Enabled = &enabled
+	apiUser.Identities["email@test.com"] = &api.IdentitySource{Kind: api.IdentityKind_IDENTITY_KIND_EMAIL}
+	apiUser.Identities["alias@test.com"] = &api.IdentitySource{Kind: api.IdentityKind_IDENTITY_KIND_EMAIL}
+	apiUser.Identities["+40722332233"] = &api.IdentitySource{Kind: api.IdentityKind_IDENTITY_KIND_PHONE}
+	apiUser.Identities["name@tenant.onmicrosoft.com"] = &api.IdentitySource{Kind: api.IdentityKind_IDENTITY_KIND_USERNAME}
+	apiUser.Attributes.Properties.Fields["department"] = structpb.NewStringValue("Engineering")
+	apiUser.Attributes.Properties.Fields["unknown"] = structpb.NewStringValue("ignored")
+
+	azureadUser := transform.ToAzureAD(apiUser)
+
+	assert.Equal(false, *azureadUser.GetAccountEnabled())
+	assert.Equal([]string{"alias@test.com"}, azureadUser.GetOtherMails())
+	assert.Equal("+40722332233", *azureadUser.GetMobilePhone())
+	assert.Equal("name@tenant.onmicrosoft.com", *azureadUser.GetUserPrincipalName())
+	assert.Equal("Engineering", *azureadUser.GetDepartment())
+	assert.Equal("name@tenant.onmicrosoft.com", transform.UserPrincipalName(apiUser))
+}
+
+func TestRemoved(t *testing.T) {
+	assert := require.New(t)
+	azureadUser := models.NewUser()
+	id := "1"
+	azureadUser.SetId(&id)
+
+	apiUser := transform.Removed(azureadUser)
+
+	assert.Equal("1", apiUser.Id)
+	assert.True(apiUser.Deleted)
+	assert.Equal(api.IdentityKind_IDENTITY_KIND_PID, apiUser.Identities["1"].Kind)
+}
+
+func TestInclusionExcluded(t *testing.T) {
+	enabled, disabled := true, false
+	member, guest := "Member", "guest"
+
+	newUser := func(accountEnabled *bool, userType *string, resource *bool) models.Userable {
+		user := models.NewUser()
+		user.SetAccountEnabled(accountEnabled)
+		user.SetUserType(userType)
+		user.SetIsResourceAccount(resource)
+		return user
+	}
+	serviceAccount := newUser(&enabled, &member, nil)
+	upn := "svc-backup@contoso.onmicrosoft.com"
+	serviceAccount.SetUserPrincipalName(&upn)
+	pattern := regexp.MustCompile(`^svc-`)
+	invited := newUser(&enabled, nil, nil)
+	invitation := transform.CreationTypeInvitation
+	invited.SetCreationType(&invitation)
+
+	tests := []struct {
+		name      string
+		inclusion transform.Inclusion
+		user      models.Userable
+		want      string
+	}{
+		{name: "enabled member", user: newUser(&enabled, &member, &disabled), want: ""},
+		{name: "disabled", user: newUser(&disabled, &member, nil), want: transform.ExcludedDisabled},
+		{name: "guest", user: newUser(&enabled, &guest, nil), want: transform.ExcludedGuest},
+		{name: "invited guest without user type", user: invited, want: transform.ExcludedGuest},
+		{name: "resource account", user: newUser(&enabled, &member, &enabled), want: transform.ExcludedServiceAccount},
+		{name: "service account", inclusion: transform.Inclusion{ServiceAccountPattern: pattern}, user: serviceAccount, want: transform.ExcludedServiceAccount},
+		{name: "service account without pattern", user: serviceAccount, want: ""},
+		{name: "included service account", inclusion: transform.Inclusion{ServiceAccounts: true, ServiceAccountPattern: pattern}, user: serviceAccount, want: ""},
+		{name: "disabled guest", user: newUser(&disabled, &guest, nil), want: transform.ExcludedDisabled},
+		{name: "included disabled guest", inclusion: transform.Inclusion{Disabled: true}, user: newUser(&disabled, &guest, nil), want: transform.ExcludedGuest},
+		{name: "all included", inclusion: transform.Inclusion{Disabled: true, Guests: true, ServiceAccounts: true}, user: newUser(&disabled, &guest, &enabled), want: ""},
+		{name: "missing properties", user: newUser(nil, nil, nil), want: ""},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			require.New(t).Equal(tt.want, tt.inclusion.Excluded(tt.user))
+		})
+	}
+}
+
+func TestGroupRoles(t *testing.T) {
+	assert := require.New(t)
+
+	group := func(id, name, nickname string) models.Groupable {
+		g := models.NewGroup()
+		g.SetId(&id)
+		g.SetDisplayName(&name)
+		g.SetMailNickname(&nickname)
+		return g
+	}
+	groups := []models.Groupable{
+		group("2", "Sales", "sales"),
+		group("1", "App Admins", "app-admins"),
+		group("3", "Sales", "sales-emea"),
+	}
+
+	assert.Equal([]string{"App Admins", "Sales"}, transform.GroupRoles(groups, "", nil))
+	assert.Equal([]string{"1", "2", "3"}, transform.GroupRoles(groups, transform.GroupPropertyID, nil))
+	assert.Equal([]string{"sales", "sales-emea"},
+		transform.GroupRoles(groups, transform.GroupPropertyMailNickname, regexp.MustCompile("^sales")))
+}
+
+func TestAppRoleApplications(t *testing.T) {
+	assert := require.New(t)
+
+	resourceID := uuid.New()
+	readerID, writerID := uuid.New(), uuid.New()
+
+	role := func(id uuid.UUID, value string) models.AppRoleable {
+		r := models.NewAppRole()
+		r.SetId(&id)
+		r.SetValue(&value)
+		return r
+	}
+	sp := models.NewServicePrincipal()
+	appID := "00000000-aaaa-bbbb-cccc-000000000001"
+	sp.SetAppId(&appID)
+	sp.SetAppRoles([]models.AppRoleable{role(readerID, "Reader"), role(writerID, "Writer")})
+
+	assignment := func(roleID uuid.UUID, resourceName string) models.AppRoleAssignmentable {
+		a := models.NewAppRoleAssignment()
+		a.SetResourceId(&resourceID)
+		a.SetAppRoleId(&roleID)
+		if resourceName != "" {
+			a.SetResourceDisplayName(&resourceName)
+		}
+		return a
+	}
+	principals := map[string]models.ServicePrincipalable{resourceID.String(): sp}
+
+	apps := transform.AppRoleApplications([]models.AppRoleAssignmentable{
+		assignment(writerID, "Expenses"),
+		assignment(readerID, "Expenses"),
+		assignment(writerID, "Expenses"),
+		assignment(uuid.Nil, "Expenses"),
+	}, principals)
+	assert.Len(apps, 1)
+	assert.Equal([]string{"Reader", "Writer"}, apps["Expenses"].Roles)
+
+	apps = transform.AppRoleApplications([]models.AppRoleAssignmentable{assignment(uuid.Nil, "")}, principals)
+	assert.Contains(apps, appID)
+	assert.Empty(apps[appID].Roles)
+}